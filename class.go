@@ -0,0 +1,127 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"unicode"
+)
+
+// Class is a set of runes (a character class) optimized for ASCII
+// input. ASCII runes are kept in a 128-bit bitset checked with a single
+// mask operation while others are found with a binary search of
+// sorted, merged ranges (or of the Unicode tables added). The zero
+// value is an empty Class ready to use.
+type Class struct {
+	ascii  [2]uint64
+	ranges [][2]rune             // inclusive, non-ASCII, sorted, merged
+	tables []*unicode.RangeTable // see AddTable, non-ASCII checked
+}
+
+// Add adds every rune of the string to the Class and returns it for
+// chaining.
+func (c *Class) Add(runes string) *Class {
+	for _, r := range runes {
+		c.AddRange(r, r)
+	}
+	return c
+}
+
+// AddRange adds every rune from lo to hi (inclusive) to the Class and
+// returns it for chaining.
+func (c *Class) AddRange(lo, hi rune) *Class {
+	if lo < 0 {
+		lo = 0
+	}
+	for ; lo <= hi && lo < 128; lo++ {
+		c.ascii[lo>>6] |= 1 << (lo & 63)
+	}
+	if lo > hi {
+		return c
+	}
+	i := sort.Search(len(c.ranges), func(i int) bool { return c.ranges[i][1] >= lo-1 })
+	j := i
+	for ; j < len(c.ranges) && c.ranges[j][0] <= hi+1; j++ {
+		lo, hi = min(lo, c.ranges[j][0]), max(hi, c.ranges[j][1])
+	}
+	c.ranges = slices.Replace(c.ranges, i, j, [2]rune{lo, hi})
+	return c
+}
+
+// AddFold is the same as AddRange but also adds every rune that is
+// equivalent to one in the range under simple case folding (see
+// unicode.SimpleFold) so that 'a' to 'z' also matches 'A' to 'Z' (and
+// the Kelvin sign, and such) without duplicating range pairs. Regular
+// expressions do the same with the (?i) flag.
+func (c *Class) AddFold(lo, hi rune) *Class {
+	c.AddRange(lo, hi)
+	for r := lo; r <= hi; r++ {
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < lo || f > hi {
+				c.AddRange(f, f)
+			}
+		}
+	}
+	return c
+}
+
+// AddTable adds every rune of the Unicode range table to the Class and
+// returns it for chaining. The table is kept as is (rather than copied
+// rune by rune) and checked with unicode.Is for anything not ASCII.
+func (c *Class) AddTable(t *unicode.RangeTable) *Class {
+	for _, r := range t.R16 {
+		for x := rune(r.Lo); x <= rune(r.Hi) && x < 128; x += rune(r.Stride) {
+			c.ascii[x>>6] |= 1 << (x & 63)
+		}
+	}
+	c.tables = append(c.tables, t)
+	return c
+}
+
+// AddUnicode adds every rune of the named Unicode general categories
+// (ex: "Lu", "L"), scripts (ex: "Greek"), or properties (ex: "White_Space")
+// so that grammars can say "a Greek letter" without importing tables
+// (see unicode.Categories, unicode.Scripts, and unicode.Properties).
+// Returns the Class for chaining. Panics if a name is not found since
+// names are almost always constants known when the Class is built (see
+// regexp.MustCompile). Regular expressions already support the same
+// names (ex: \p{Greek}).
+func (c *Class) AddUnicode(names ...string) *Class {
+	for _, name := range names {
+		t, has := unicode.Categories[name]
+		if !has {
+			t, has = unicode.Scripts[name]
+		}
+		if !has {
+			t, has = unicode.Properties[name]
+		}
+		if !has {
+			panic(fmt.Sprintf("scan: unknown Unicode class name: %q", name))
+		}
+		c.AddTable(t)
+	}
+	return c
+}
+
+// Contains returns true if the rune is in the Class.
+func (c *Class) Contains(r rune) bool {
+	if r >= 0 && r < 128 {
+		return c.ascii[r>>6]&(1<<(r&63)) != 0
+	}
+	i := sort.Search(len(c.ranges), func(i int) bool { return c.ranges[i][1] >= r })
+	if i < len(c.ranges) && c.ranges[i][0] <= r {
+		return true
+	}
+	for _, t := range c.tables {
+		if unicode.Is(t, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// In returns true if the last rune scanned (R) is in the Class.
+func (s *R) In(c *Class) bool { return c.Contains(s.R) }
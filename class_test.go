@@ -0,0 +1,65 @@
+package scan_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleClass() {
+	ident := new(scan.Class).AddRange('a', 'z').AddRange('A', 'Z').Add("_ä")
+	ident.AddRange('α', 'ω')
+
+	s := new(scan.R)
+	s.B = []byte("x_Yäβ1-")
+	for s.Scan() {
+		fmt.Print(s.In(ident), " ")
+	}
+	fmt.Println()
+
+	// Output:
+	// true true true true true false false
+}
+
+func ExampleClass_AddUnicode() {
+	greek := new(scan.Class).AddUnicode("Greek", "Nd")
+
+	s := new(scan.R)
+	s.B = []byte("αΩ9٣xΣ")
+	var in []bool
+	for s.Scan() {
+		in = append(in, s.In(greek))
+	}
+	fmt.Println(in)
+
+	defer func() { fmt.Println(recover()) }()
+	greek.AddUnicode("Klingon")
+
+	// Output:
+	// [true true true true false true]
+	// scan: unknown Unicode class name: "Klingon"
+}
+
+func ExampleClass_AddFold() {
+	letters := new(scan.Class).AddFold('a', 'z').AddFold('ä', 'ä')
+
+	s := new(scan.R)
+	s.B = []byte("aZÄ\u212a1") // Kelvin sign
+	var in []bool
+	for s.Scan() {
+		in = append(in, s.In(letters))
+	}
+	fmt.Println(in)
+
+	// Output:
+	// [true true true true false]
+}
+
+func BenchmarkClass_Contains(b *testing.B) {
+	c := new(scan.Class).AddUnicode("L", "Nd").AddFold('a', 'z')
+	for range b.N {
+		c.Contains('ж')
+		c.Contains('€')
+	}
+}
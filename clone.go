@@ -0,0 +1,44 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a copy of the scanner with its own cursor, Errors,
+// breakpoints, and watches so that a speculative parse can be run on the
+// copy and simply discarded if it fails (rather than saving a Pointer
+// and returning with Goto). The buffer (B) is shared since scanning
+// never modifies it, except for a stream (see Stream) which is copied
+// and detached from its reader so the clone can only scan what has
+// already been read. A clone of a memory-mapped scanner (see
+// OpenMapped) shares the mapping without owning it and so must not be
+// used after the original is unmapped.
+func (s *R) Clone() *R {
+	c := *s
+	c.Errors = slices.Clone(s.Errors)
+	c.Files = slices.Clone(s.Files)
+	c.NewLine = slices.Clone(s.NewLine)
+	c.breakAt = maps.Clone(s.breakAt)
+	c.breakOn = maps.Clone(s.breakOn)
+	c.watches = slices.Clone(s.watches)
+	c.removed = slices.Clone(s.removed)
+	c.mapped = nil
+	if s.src != nil {
+		c.B = slices.Clone(s.B)
+		c.src = nil
+	}
+	return &c
+}
+
+// Clone returns a copy of the token scanner with its own cursor, Errors,
+// and Stack sharing the (unmodified) Tokens.
+func (t *T) Clone() *T {
+	c := *t
+	c.Errors = slices.Clone(t.Errors)
+	c.Stack = slices.Clone(t.Stack)
+	return &c
+}
@@ -0,0 +1,44 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Clone() {
+	s := new(scan.R)
+	s.B = []byte("foo(bar)")
+	s.ScanN(3)
+
+	// try a call on a disposable copy
+	try := s.Clone()
+	try.Expect("(")
+	try.TakeWhile(func(r rune) bool { return r != ')' })
+	try.Expect(",")
+	fmt.Println(try.Errors)
+
+	// the original is untouched
+	s.Print()
+	fmt.Println(s.Errors)
+
+	// Output:
+	// [expected ',' at U+0072 'r' 1,7-7 (7-7)]
+	// 3 'o' "(bar)"
+	// []
+}
+
+func ExampleT_Clone() {
+	t := new(scan.T)
+	t.Toks = []scan.Token{{Type: 1, Text: "x"}, {Type: 2, Text: "+"}}
+	t.Enter("Expr")
+	c := t.Clone()
+	c.Scan()
+	c.Enter("Term")
+	fmt.Println(t, t.Stack)
+	fmt.Println(c, c.Stack)
+
+	// Output:
+	// 0 0 "" [Expr]
+	// 1 1 "x" [Expr Term]
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Scan runs a grammar against one or more files (or standard input) and
+prints the resulting tokens so that grammars can be developed without
+writing a throwaway Go program for every experiment. Any errors are
+reported (see scan.R.Report) to standard error and the exit status is
+then one. Grammars are either the lexer rules of ANTLR4 .g4 files (see
+the g4 package) or any other file of lexer rules (see
+scan.ParseLexer). With -i an interactive expression REPL
+is started instead (see scan.REPL).
+
+	Usage: scan -g GRAMMAR [-o tokens|json|color] [FILE ...]
+	       scan -i
+
+The tokens format prints one token per line with its position, type
+name, and quoted text. The json format prints one JSON object per token
+(and reports errors as JSON). The color format prints the input itself
+with each token type highlighted using ANSI terminal escapes.
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/rwxrob/scan"
+	"github.com/rwxrob/scan/g4"
+)
+
+func main() { os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)) }
+
+// colors are the ANSI foreground colors cycled through by token type.
+var colors = []string{"31", "32", "33", "34", "35", "36", "91", "92", "93", "94", "95", "96"}
+
+func run(args []string, in io.Reader, out, errout io.Writer) int {
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
+	flags.SetOutput(errout)
+	grammar := flags.String("g", "", "grammar file (.g4 or lexer rules)")
+	format := flags.String("o", "tokens", "output format (tokens, json, color)")
+	repl := flags.Bool("i", false, "interactive expression REPL")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *repl {
+		if err := scan.REPL(in, out); err != nil {
+			fmt.Fprintln(errout, "scan:", err)
+			return 1
+		}
+		return 0
+	}
+	if *grammar == "" {
+		fmt.Fprintln(errout, "scan: grammar (-g) required")
+		flags.Usage()
+		return 2
+	}
+	switch *format {
+	case "tokens", "json", "color":
+	default:
+		fmt.Fprintf(errout, "scan: unknown output format: %q\n", *format)
+		return 2
+	}
+
+	src, err := os.ReadFile(*grammar)
+	if err != nil {
+		fmt.Fprintln(errout, "scan:", err)
+		return 1
+	}
+	g := new(g4.Grammar)
+	if filepath.Ext(*grammar) == ".g4" {
+		g, err = g4.Import(src)
+	} else {
+		g.Lexer, g.Types, err = scan.ParseLexer(string(src))
+	}
+	if err != nil {
+		fmt.Fprintf(errout, "scan: %v: %v\n", *grammar, err)
+		return 1
+	}
+
+	s := new(scan.R)
+	s.Out = errout
+	s.JSON = *format == "json"
+	if flags.NArg() == 0 {
+		s.Buffer(in)
+	} else if err := s.Open(flags.Args()...); err != nil {
+		fmt.Fprintln(errout, "scan:", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(out)
+	last := 0
+	for t, err := range g.Lexer.Tokens(s) {
+		if err != nil {
+			break
+		}
+		switch *format {
+		case "tokens":
+			fmt.Fprintf(out, "%v %v %q\n", t.Pos, g.Types[t.Type], t.Text)
+		case "json":
+			enc.Encode(struct {
+				Type string        `json:"type"`
+				Text string        `json:"text"`
+				Pos  scan.Position `json:"pos"`
+			}{g.Types[t.Type], t.Text, t.Pos})
+		case "color":
+			out.Write(s.B[last:t.B])
+			fmt.Fprintf(out, "\x1b[%vm%v\x1b[0m", colors[t.Type%len(colors)], t.Text)
+			last = t.E
+		}
+	}
+	if *format == "color" {
+		out.Write(s.B[last:s.P])
+	}
+
+	if len(s.Errors) > 0 {
+		s.Report()
+		return 1
+	}
+	return 0
+}
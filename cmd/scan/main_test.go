@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func Example_run() {
+	code1 := run([]string{"-g", "testdata/expr.g4", "testdata/bad.txt"},
+		nil, os.Stdout, os.Stdout)
+	fmt.Println("---")
+	code2 := run([]string{"-g", "testdata/expr.lex"},
+		strings.NewReader("(a+1)"), os.Stdout, os.Stdout)
+	fmt.Println(code1, code2)
+
+	// Output:
+	// testdata/bad.txt U+0078 'x' 1,1-1 (1-1) ID "x"
+	// testdata/bad.txt U+003D '=' 1,3-3 (3-3) OP "="
+	// error: unexpected '$' at testdata/bad.txt U+0024 '$' 1,5-5 (5-5)
+	// ---
+	// U+0028 '(' 1,1-1 (1-1) OP "("
+	// U+0061 'a' 1,2-2 (2-2) ID "a"
+	// U+002B '+' 1,3-3 (3-3) OP "+"
+	// U+0031 '1' 1,4-4 (4-4) NUM "1"
+	// U+0029 ')' 1,5-5 (5-5) OP ")"
+	// 1 0
+}
@@ -0,0 +1,6 @@
+lexer grammar Expr;
+
+ID  : [a-zA-Z_] [a-zA-Z_0-9]* ;
+NUM : [0-9]+ ('.' [0-9]+)? ;
+OP  : [-+*/=()] ;
+WS  : [ \t\r\n]+ -> skip ;
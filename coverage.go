@@ -0,0 +1,57 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coverage counts how often each of the Rules of a Lexer (by index) was
+// attempted and how often it matched (produced the Token) so that dead
+// or untested rules can be found after running a test suite or corpus
+// (see Lexer.Cover). The zero value is ready to use.
+type Coverage struct {
+	Attempts []int
+	Matches  []int
+}
+
+func (c *Coverage) add(counts *[]int, i int) {
+	for len(*counts) <= i {
+		*counts = append(*counts, 0)
+	}
+	(*counts)[i]++
+}
+
+func count(counts []int, i int) int {
+	if i < len(counts) {
+		return counts[i]
+	}
+	return 0
+}
+
+// Report returns one line for each of the Rules of the Lexer with its
+// type (the name from names if any), expression, and whether it matched
+// (and how often out of its attempts), never matched, or was never even
+// reached.
+func (c Coverage) Report(l Lexer, names []string) string {
+	var buf strings.Builder
+	for i, rule := range l.Rules {
+		name := fmt.Sprint(rule.Type)
+		if rule.Type >= 0 && rule.Type < len(names) {
+			name = names[rule.Type]
+		}
+		fmt.Fprintf(&buf, "%v %v: ", name, rule.Re)
+		a, m := count(c.Attempts, i), count(c.Matches, i)
+		switch {
+		case a == 0:
+			buf.WriteString("never reached\n")
+		case m == 0:
+			fmt.Fprintf(&buf, "never matched (%v attempts)\n", a)
+		default:
+			fmt.Fprintf(&buf, "matched %v of %v\n", m, a)
+		}
+	}
+	return buf.String()
+}
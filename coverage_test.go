@@ -0,0 +1,33 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleCoverage() {
+	l, names := scan.MustParseLexer(`
+-space \s+
+Num    \d+
+Word   \p{L}+
+Str    "[^"]*"
+Op     [-+*/=]
+Op     \*\*
+`)
+	l.Cover = new(scan.Coverage)
+
+	s := new(scan.R)
+	s.Buffer("x = 42 * y")
+	l.Lex(s)
+
+	fmt.Print(l.Cover.Report(l, names))
+
+	// Output:
+	// space \s+: matched 4 of 9
+	// Num \d+: matched 1 of 5
+	// Word \p{L}+: matched 2 of 4
+	// Str "[^"]*": never matched (2 attempts)
+	// Op [-+*/=]: matched 2 of 2
+	// Op \*\*: never reached
+}
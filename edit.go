@@ -0,0 +1,58 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Edit is the replacement of the text within the Span of a buffer. An
+// empty Span (B == E) inserts the Text and an empty Text deletes.
+type Edit struct {
+	Span
+	Text string
+}
+
+// Edits collects replacements (usually added while scanning) so that
+// they can be applied to the original buffer in a single pass (see
+// Apply) as formatters and codemod tools require.
+type Edits []Edit
+
+// AddReplacement adds an Edit replacing the Span with the text.
+func (e *Edits) AddReplacement(sp Span, text string) {
+	*e = append(*e, Edit{sp, text})
+}
+
+// Apply returns a new buffer with every Edit applied to the original
+// buffer (which is left unchanged). Edits may be added in any order but
+// insertions at the same offset are kept in the order added and always
+// come before a replacement (or deletion) starting there. Returns an
+// error if any Span does not fit the buffer or if any two overlap.
+func (e Edits) Apply(buf []byte) ([]byte, error) {
+	edits := make(Edits, len(e))
+	copy(edits, e)
+	sort.SliceStable(edits, func(i, j int) bool {
+		a, b := edits[i], edits[j]
+		if a.B != b.B {
+			return a.B < b.B
+		}
+		return a.E == a.B && b.E != b.B
+	})
+	out := make([]byte, 0, len(buf))
+	var last int
+	for _, ed := range edits {
+		if ed.B < 0 || ed.E > len(buf) || ed.B > ed.E {
+			return nil, fmt.Errorf("edit %v does not fit buffer (%v)",
+				ed.Span, len(buf))
+		}
+		if ed.B < last {
+			return nil, fmt.Errorf("edit %v overlaps another", ed.Span)
+		}
+		out = append(out, buf[last:ed.B]...)
+		out = append(out, ed.Text...)
+		last = ed.E
+	}
+	return append(out, buf[last:]...), nil
+}
@@ -0,0 +1,52 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleEdits() {
+	s := new(scan.R)
+	s.B = []byte("let x=1;let y=2;")
+
+	var edits scan.Edits
+	for s.Scan() {
+		switch s.R {
+		case '=':
+			edits.AddReplacement(s.Last(), " = ")
+		case ';':
+			edits.AddReplacement(scan.Span{B: s.P, E: s.P}, "\n")
+		}
+	}
+	edits.AddReplacement(scan.Span{B: 0, E: 0}, "// generated\n")
+
+	out, err := edits.Apply(s.B)
+	fmt.Printf("%s%v\n", out, err)
+
+	edits.AddReplacement(scan.Span{B: 4, E: 7}, "z")
+	_, err = edits.Apply(s.B)
+	fmt.Println(err)
+
+	// Output:
+	// // generated
+	// let x = 1;
+	// let y = 2;
+	// <nil>
+	// edit {5 6} overlaps another
+}
+
+func ExampleEdits_Apply() {
+	buf := []byte("a = old")
+	insert := scan.Edit{Span: scan.Span{B: 4, E: 4}, Text: "(new) "}
+	replace := scan.Edit{Span: scan.Span{B: 4, E: 7}, Text: "new"}
+
+	for _, edits := range []scan.Edits{{insert, replace}, {replace, insert}} {
+		out, err := edits.Apply(buf)
+		fmt.Printf("%s %v\n", out, err)
+	}
+
+	// Output:
+	// a = (new) new <nil>
+	// a = (new) new <nil>
+}
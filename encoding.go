@@ -0,0 +1,94 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encodings detected when R.Transcode is set.
+const (
+	UTF8    = `UTF-8`
+	UTF16LE = `UTF-16LE`
+	UTF16BE = `UTF-16BE`
+	Latin1  = `ISO-8859-1`
+)
+
+// transcode detects the encoding of the input returning it converted
+// to UTF-8 (without any byte order mark) along with the name of the
+// original encoding. UTF-16 is detected by its byte order mark or, when
+// missing, by the zero bytes of mostly ASCII text in every other byte.
+// Anything else that is not valid UTF-8 is assumed to be Latin-1 (ISO
+// 8859-1) which maps each byte directly to a rune.
+func transcode(b []byte) ([]byte, string) {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return b[3:], UTF8
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		return decode16(b[2:], false), UTF16LE
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return decode16(b[2:], true), UTF16BE
+	}
+	if enc := guess16(b); enc != "" {
+		return decode16(b, enc == UTF16BE), enc
+	}
+	if utf8.Valid(b) {
+		return b, UTF8
+	}
+	out := make([]byte, 0, len(b)*2)
+	for _, c := range b {
+		out = utf8.AppendRune(out, rune(c))
+	}
+	return out, Latin1
+}
+
+// guess16 returns UTF16LE or UTF16BE if most of the odd or even bytes
+// (respectively) of the beginning of the input are zero (and almost none
+// of the others) or an empty string otherwise.
+func guess16(b []byte) string {
+	n := min(len(b), 1024) &^ 1
+	if n < 4 {
+		return ""
+	}
+	var even, odd int
+	for i := 0; i < n; i += 2 {
+		if b[i] == 0 {
+			even++
+		}
+		if b[i+1] == 0 {
+			odd++
+		}
+	}
+	pairs := n / 2
+	switch {
+	case odd*10 > pairs*4 && even*20 < pairs:
+		return UTF16LE
+	case even*10 > pairs*4 && odd*20 < pairs:
+		return UTF16BE
+	}
+	return ""
+}
+
+// decode16 returns the UTF-16 input (little-endian unless big) as UTF-8
+// (invalid surrogates and an odd last byte become utf8.RuneError).
+func decode16(b []byte, big bool) []byte {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		lo, hi := b[i*2], b[i*2+1]
+		if big {
+			lo, hi = hi, lo
+		}
+		units[i] = uint16(hi)<<8 | uint16(lo)
+	}
+	out := make([]byte, 0, len(b))
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	if len(b)%2 == 1 {
+		out = utf8.AppendRune(out, utf8.RuneError)
+	}
+	return out
+}
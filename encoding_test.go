@@ -0,0 +1,34 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Transcode() {
+	s := new(scan.R)
+	s.Transcode = true
+	for _, in := range [][]byte{
+		{0xEF, 0xBB, 0xBF, 'h', 'i'},                         // UTF-8 with BOM
+		{0xFF, 0xFE, 'h', 0, 'i', 0, 0x3D, 0xD8, 0x7F, 0xDC}, // UTF-16LE with BOM
+		{0, 'h', 0, 'i', 0, '!', 0, '\n'},                    // UTF-16BE without BOM
+		{'c', 'a', 'f', 0xE9},                                // Latin-1
+		[]byte("café"),                                       // UTF-8
+	} {
+		s.Buffer(in)
+		fmt.Printf("%v %q\n", s.Encoding, s.B)
+	}
+
+	s.Transcode = false
+	s.Buffer([]byte{'c', 'a', 'f', 0xE9})
+	fmt.Printf("%q %q\n", s.Encoding, s.B)
+
+	// Output:
+	// UTF-8 "hi"
+	// UTF-16LE "hi👿"
+	// UTF-16BE "hi!\n"
+	// ISO-8859-1 "café"
+	// UTF-8 "café"
+	// "" "caf\xe9"
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package calc is an example arithmetic calculator built with a scan.Lexer
+and a hand-written scan.T parser that evaluates as it goes (rather than
+building a tree) with the usual precedence:
+
+	Expr   <- Term (('+' / '-') Term)*
+	Term   <- Factor (('*' / '/') Factor)*
+	Factor <- '-' Factor / Num / '(' Expr ')'
+*/
+package calc
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/rwxrob/scan"
+)
+
+// Token types
+const (
+	Space = iota
+	Num
+	Add
+	Sub
+	Mul
+	Div
+	Open
+	Close
+)
+
+// Lexer produces the Tokens of an arithmetic expression with the value
+// of every Num already converted to a float64.
+var Lexer = scan.Lexer{Rules: []scan.Rule{
+	{Type: Space, Re: regexp.MustCompile(`\s+`), Skip: true},
+	{Type: Num, Re: regexp.MustCompile(`\d+(\.\d+)?`),
+		Value: func(text string) (any, error) {
+			return strconv.ParseFloat(text, 64)
+		}},
+	{Type: Add, Re: regexp.MustCompile(`\+`)},
+	{Type: Sub, Re: regexp.MustCompile(`-`)},
+	{Type: Mul, Re: regexp.MustCompile(`\*`)},
+	{Type: Div, Re: regexp.MustCompile(`/`)},
+	{Type: Open, Re: regexp.MustCompile(`\(`)},
+	{Type: Close, Re: regexp.MustCompile(`\)`)},
+}}
+
+// Eval returns the value of the arithmetic expression or the first
+// error (with its position) encountered.
+func Eval(expr string) (float64, error) {
+	var v float64
+	p := scan.Pipeline{
+		Lexer: Lexer,
+		Parse: func(t *scan.T) bool {
+			var ok bool
+			if v, ok = Expr(t); !ok {
+				return false
+			}
+			if !t.End() {
+				t.Error("unexpected %q", t.Toks[t.P].Text)
+				return false
+			}
+			return true
+		},
+	}
+	s := new(scan.R)
+	s.Buffer(expr)
+	_, err := p.Run(s)
+	return v, err
+}
+
+// Expr <- Term (('+' / '-') Term)*
+func Expr(t *scan.T) (float64, bool) {
+	v, ok := Term(t)
+	if !ok {
+		return 0, false
+	}
+	for t.In(Add, Sub) {
+		t.Scan()
+		op := t.T.Type
+		r, ok := Term(t)
+		if !ok {
+			return 0, false
+		}
+		if op == Add {
+			v += r
+		} else {
+			v -= r
+		}
+	}
+	return v, true
+}
+
+// Term <- Factor (('*' / '/') Factor)*
+func Term(t *scan.T) (float64, bool) {
+	v, ok := Factor(t)
+	if !ok {
+		return 0, false
+	}
+	for t.In(Mul, Div) {
+		t.Scan()
+		op := t.T.Type
+		r, ok := Factor(t)
+		if !ok {
+			return 0, false
+		}
+		if op == Mul {
+			v *= r
+			continue
+		}
+		if r == 0 {
+			t.Error("division by zero")
+			return 0, false
+		}
+		v /= r
+	}
+	return v, true
+}
+
+// Factor <- '-' Factor / Num / '(' Expr ')'
+func Factor(t *scan.T) (float64, bool) {
+	switch {
+	case t.In(Sub):
+		t.Scan()
+		v, ok := Factor(t)
+		return -v, ok
+	case t.In(Num):
+		t.Scan()
+		return t.T.V.(float64), true
+	case t.In(Open):
+		t.Scan()
+		v, ok := Expr(t)
+		if !ok {
+			return 0, false
+		}
+		if !t.In(Close) {
+			t.Error("expected ')'")
+			return 0, false
+		}
+		t.Scan()
+		return v, true
+	}
+	t.Error("expected number or '('")
+	return 0, false
+}
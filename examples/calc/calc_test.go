@@ -0,0 +1,39 @@
+package calc_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan/examples/calc"
+)
+
+func ExampleEval() {
+	for _, expr := range []string{
+		"1 + 2 * 3",
+		"(1 + 2) * 3",
+		"10 / 4 - -1",
+		"2 * (3 + 4) * 5 / 7",
+		"1 +",
+		"(1 + 2",
+		"1 / 0",
+		"1 2",
+		"1 $ 2",
+	} {
+		v, err := calc.Eval(expr)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 7
+	// 9
+	// 3.5
+	// 10
+	// expected number or '(' at U+002B '+' 1,3-3 (3-3)
+	// expected ')' at U+0032 '2' 1,6-6 (6-6)
+	// division by zero at U+0030 '0' 1,5-5 (5-5)
+	// unexpected "2" at U+0032 '2' 1,3-3 (3-3)
+	// unexpected '$' at U+0024 '$' 1,3-3 (3-3)
+}
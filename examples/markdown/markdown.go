@@ -0,0 +1,261 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package markdown is an example hand-written scan.R scanner for the most
+common Markdown inline elements (code spans, strong, emphasis, and
+links) producing a tree of positioned Nodes. It is a realistic exercise
+of lookahead (Peek), lookbehind, and backtracking (Ptr and Goto) and
+a building block for README-style tooling. Anything that does not close
+properly is kept as plain Text (as Markdown requires) rather than being
+reported as an error.
+*/
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rwxrob/scan"
+)
+
+// Node types
+const (
+	Text = iota
+	Code
+	Emph
+	Strong
+	Link
+)
+
+var names = []string{"Text", "Code", "Emph", "Strong", "Link"}
+
+// Node is a single inline element. The Span includes any delimiters
+// while Text is only set for Text and Code (without the backticks) and
+// URL only for Link.
+type Node struct {
+	Type  int
+	Span  scan.Span
+	Pos   scan.Position // of first rune
+	Text  string
+	URL   string
+	Nodes []Node
+}
+
+// String returns the Node and its Nodes indented by depth.
+func (n Node) String() string {
+	var buf strings.Builder
+	n.write(&buf, 0)
+	return buf.String()
+}
+
+func (n Node) write(buf *strings.Builder, depth int) {
+	fmt.Fprintf(buf, "%v%v %v,%v", strings.Repeat("  ", depth),
+		names[n.Type], n.Pos.Line, n.Pos.LRune)
+	if n.Text != "" {
+		fmt.Fprintf(buf, " %q", n.Text)
+	}
+	if n.URL != "" {
+		fmt.Fprintf(buf, " <%v>", n.URL)
+	}
+	buf.WriteString("\n")
+	for _, c := range n.Nodes {
+		c.write(buf, depth+1)
+	}
+}
+
+// Parse scans all of the inline elements from the current position of
+// the scanner to the end of its buffer.
+func Parse(s *scan.R) []Node {
+	nodes, _ := (&parser{s: s, memo: map[attempt]result{}}).inline("", false)
+	var offs []int
+	walk(nodes, func(n *Node) { offs = append(offs, n.Span.B+1) })
+	pos := s.Positions(offs...)
+	var i int
+	walk(nodes, func(n *Node) { n.Pos = pos[i]; i++ })
+	return nodes
+}
+
+func walk(nodes []Node, fn func(n *Node)) {
+	for i := range nodes {
+		fn(&nodes[i])
+		walk(nodes[i].Nodes, fn)
+	}
+}
+
+// parser remembers the result of every attempt to scan inline Nodes up
+// to a closer from a given offset so that backtracking (an unclosed
+// delimiter or link discarding everything nested within it) never
+// scans the same content for the same closer twice. Without it input
+// such as "*a _b *a _b ..." or "[[[[..." takes exponential time.
+type parser struct {
+	s    *scan.R
+	memo map[attempt]result
+}
+
+type attempt struct {
+	p      int
+	closer string
+}
+
+type result struct {
+	nodes []Node
+	end   int
+	ok    bool
+}
+
+// inline scans Nodes until the closer (if any) is found returning false
+// if it never was.
+func (x *parser) inline(closer string, flanking bool) ([]Node, bool) {
+	at := attempt{x.s.P, closer}
+	if r, done := x.memo[at]; done {
+		x.s.Jump(r.end)
+		return r.nodes, r.ok
+	}
+	nodes, ok := x.scan(closer, flanking)
+	x.memo[at] = result{nodes, x.s.P, ok}
+	return nodes, ok
+}
+
+func (x *parser) scan(closer string, flanking bool) ([]Node, bool) {
+	s := x.s
+	var nodes []Node
+	text := -1
+
+	flush := func(end int) {
+		if text >= 0 && end > text {
+			sp := scan.Span{B: text, E: end}
+			nodes = append(nodes, Node{Type: Text, Span: sp, Text: sp.Text(s.B)})
+		}
+		text = -1
+	}
+
+	for !s.End() {
+		p := s.P
+
+		// the rest of a scan depends only on where it is and what closes
+		// it so reaching where the same closer already failed fails too
+		if r, done := x.memo[attempt{p, closer}]; done && !r.ok {
+			s.Jump(r.end)
+			return nodes, false
+		}
+
+		if closer != "" && s.Peek(closer) && (!flanking || afterNonSpace(s)) {
+			flush(p)
+			s.Jump(p + len(closer))
+			return nodes, true
+		}
+
+		if n, ok := code(s); ok {
+			flush(p)
+			nodes = append(nodes, n)
+			continue
+		}
+
+		if n, ok := x.delimited("**", Strong); ok {
+			flush(p)
+			nodes = append(nodes, n)
+			continue
+		}
+
+		if n, ok := x.delimited("*", Emph); ok {
+			flush(p)
+			nodes = append(nodes, n)
+			continue
+		}
+
+		if n, ok := x.delimited("_", Emph); ok {
+			flush(p)
+			nodes = append(nodes, n)
+			continue
+		}
+
+		if n, ok := x.link(); ok {
+			flush(p)
+			nodes = append(nodes, n)
+			continue
+		}
+
+		if text < 0 {
+			text = p
+		}
+		s.Scan()
+	}
+
+	flush(s.P)
+	return nodes, closer == ""
+}
+
+// afterNonSpace returns true if the rune just before the current
+// position is not a space (lookbehind).
+func afterNonSpace(s *scan.R) bool {
+	r, _ := utf8.DecodeLastRune(s.B[:s.P])
+	return s.P > 0 && !unicode.IsSpace(r)
+}
+
+// code scans a code span delimited by matching runs of backticks.
+func code(s *scan.R) (Node, bool) {
+	p := s.P
+	n := 0
+	for p+n < len(s.B) && s.B[p+n] == '`' {
+		n++
+	}
+	if n == 0 {
+		return Node{}, false
+	}
+	end := bytes.Index(s.B[p+n:], bytes.Repeat([]byte{'`'}, n))
+	if end < 0 {
+		return Node{}, false
+	}
+	text := string(s.B[p+n : p+n+end])
+	s.Jump(p + n + end + n)
+	return Node{Type: Code, Span: scan.Span{B: p, E: s.P}, Text: text}, true
+}
+
+// delimited scans a strong or emphasis Node opened by a delimiter not
+// followed by a space and closed by the same not preceded by one.
+func (x *parser) delimited(delim string, typ int) (Node, bool) {
+	s := x.s
+	if !s.Peek(delim) {
+		return Node{}, false
+	}
+	mark, p := s.Ptr(), s.P
+	s.Jump(p + len(delim))
+	r, _ := utf8.DecodeRune(s.B[s.P:])
+	if s.End() || unicode.IsSpace(r) {
+		s.Goto(mark)
+		return Node{}, false
+	}
+	kids, ok := x.inline(delim, true)
+	if !ok || len(kids) == 0 {
+		s.Goto(mark)
+		return Node{}, false
+	}
+	return Node{Type: typ, Span: scan.Span{B: p, E: s.P}, Nodes: kids}, true
+}
+
+// link scans [text](url) Nodes.
+func (x *parser) link() (Node, bool) {
+	s := x.s
+	if !s.Peek("[") {
+		return Node{}, false
+	}
+	mark, p := s.Ptr(), s.P
+	s.Jump(p + 1)
+	kids, ok := x.inline("]", false)
+	if !ok || !s.Peek("(") {
+		s.Goto(mark)
+		return Node{}, false
+	}
+	end := bytes.IndexByte(s.B[s.P:], ')')
+	if end < 0 {
+		s.Goto(mark)
+		return Node{}, false
+	}
+	url := string(s.B[s.P+1 : s.P+end])
+	s.Jump(s.P + end + 1)
+	return Node{Type: Link, Span: scan.Span{B: p, E: s.P}, URL: url, Nodes: kids}, true
+}
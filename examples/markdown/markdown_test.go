@@ -0,0 +1,55 @@
+package markdown_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/scan"
+	"github.com/rwxrob/scan/examples/markdown"
+)
+
+func ExampleParse() {
+	s := new(scan.R)
+	s.Buffer("Some *emphasis* and **strong *nested*** text\n" +
+		"with `code *not* emph` and a [**link**](https://example.com).\n" +
+		"Left * alone, 2 * 3 and [not a link] or *unclosed.")
+
+	for _, n := range markdown.Parse(s) {
+		fmt.Print(n)
+	}
+
+	// Output:
+	// Text 1,1 "Some "
+	// Emph 1,6
+	//   Text 1,7 "emphasis"
+	// Text 1,16 " and "
+	// Strong 1,21
+	//   Text 1,23 "strong "
+	//   Emph 1,30
+	//     Text 1,31 "nested"
+	// Text 1,40 " text\nwith "
+	// Code 2,6 "code *not* emph"
+	// Text 2,23 " and a "
+	// Link 2,30 <https://example.com>
+	//   Strong 2,31
+	//     Text 2,33 "link"
+	// Text 2,61 ".\nLeft * alone, 2 * 3 and [not a link] or *unclosed."
+}
+
+// Unclosed delimiters and brackets are rescanned as plain text without
+// rescanning what they contain so such input parses in linear time.
+func ExampleParse_pathological() {
+	s := new(scan.R)
+	for _, in := range []string{
+		strings.Repeat("*a _b ", 10000),
+		strings.Repeat("[", 10000),
+	} {
+		s.Buffer(in)
+		nodes := markdown.Parse(s)
+		fmt.Println(len(nodes), nodes[0].Type == markdown.Text, len(nodes[0].Text))
+	}
+
+	// Output:
+	// 1 true 60000
+	// 1 true 10000
+}
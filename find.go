@@ -0,0 +1,107 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"bytes"
+	"regexp"
+	"unicode/utf8"
+)
+
+// FindAll returns the Span of every non-overlapping match of the
+// regular expression (re) anywhere in the buffer (B) in order without
+// changing the position of the scanner (see regexp.FindAllIndex). When
+// streaming only the window currently retained in B is searched.
+func (s R) FindAll(re *regexp.Regexp) []Span {
+	locs := re.FindAllIndex(s.B, -1)
+	if locs == nil {
+		return nil
+	}
+	spans := make([]Span, len(locs))
+	for i, loc := range locs {
+		spans[i] = Span{loc[0], loc[1]}
+	}
+	return spans
+}
+
+// Find searches forward from the current position (P) for the next
+// match of the regular expression (re) and moves the scanner to the
+// beginning of it (as if the rune just before it had been scanned) so
+// that the match is next (see PeekMatch). Returns false leaving the
+// scanner unchanged if there is no match. When the expression is
+// a plain literal bytes.Index is used to jump straight to it instead.
+// When streaming (see Stream) the window is read and slid forward while
+// searching for matches no longer than the window and the scanner is
+// left at the end of the stream if there is no match.
+func (s *R) Find(re *regexp.Regexp) bool {
+	index := func(b []byte) int {
+		if loc := re.FindIndex(b); loc != nil {
+			return loc[0]
+		}
+		return -1
+	}
+	if lit, complete := re.LiteralPrefix(); complete && len(lit) > 0 {
+		index = func(b []byte) int { return bytes.Index(b, []byte(lit)) }
+	}
+	streaming := s.src != nil
+	for {
+		s.ahead(s.window + 1)
+		i := index(s.B[s.P:])
+		if s.src == nil {
+			if i < 0 {
+				if streaming {
+					s.goTo(len(s.B))
+				}
+				return false
+			}
+			s.goTo(s.P + i)
+			return true
+		}
+		// only matches starting at least a window before the end of
+		// what has been read so far are certain
+		safe := len(s.B) - s.window
+		if i >= 0 && s.P+i <= safe {
+			s.goTo(s.P + i)
+			return true
+		}
+		for safe > s.P && !utf8.RuneStart(s.B[safe]) {
+			safe--
+		}
+		s.goTo(max(safe, s.P))
+		s.fill(len(s.B) - s.P + 1)
+	}
+}
+
+// ReplaceAll returns a copy of the buffer (B) with every match of the
+// regular expression (re) (see FindAll) replaced by the string returned
+// by the function (fn) when passed the matched text. The buffer itself
+// and the scanner position are left unchanged. When streaming only the
+// window currently retained in B is copied.
+func (s R) ReplaceAll(re *regexp.Regexp, fn func(match string) string) []byte {
+	var buf []byte
+	var last int
+	for _, sp := range s.FindAll(re) {
+		buf = append(buf, s.B[last:sp.B]...)
+		buf = append(buf, fn(sp.Text(s.B))...)
+		last = sp.E
+	}
+	return append(buf, s.B[last:]...)
+}
+
+// Documents returns the Span of every document in the buffer (B) when
+// separated by matches of the regular expression (sep), such as
+// YAML-style "---" lines, so that each can be scanned in turn (by
+// setting P to its B and stopping at its E) without buffering again.
+// Separators are not included in any Span. A buffer with no separators
+// is a single document. When streaming only the window currently
+// retained in B is split.
+func (s R) Documents(sep *regexp.Regexp) []Span {
+	var docs []Span
+	var last int
+	for _, sp := range s.FindAll(sep) {
+		docs = append(docs, Span{last, sp.B})
+		last = sp.E
+	}
+	return append(docs, Span{last, len(s.B)})
+}
@@ -0,0 +1,89 @@
+package scan_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_FindAll() {
+	s := new(scan.R)
+	s.B = []byte("TODO: one\nskip\nTODO: two")
+
+	for _, sp := range s.FindAll(regexp.MustCompile(`TODO: \p{L}+`)) {
+		fmt.Println(sp, sp.Text(s.B))
+	}
+	fmt.Println(s.FindAll(regexp.MustCompile(`nope`)))
+	s.Print()
+
+	// Output:
+	// {0 9} TODO: one
+	// {15 24} TODO: two
+	// []
+	// 0 '\x00' "TODO: one\nskip\nTODO:"...
+}
+
+func ExampleR_Find() {
+	s := new(scan.R)
+	s.B = []byte("TODO: one\nskip\nTODO: two")
+	todo := regexp.MustCompile(`TODO: `)
+
+	for s.Find(todo) {
+		for end := s.P + s.PeekMatch(todo); s.P < end; {
+			s.Scan()
+		}
+		s.Print()
+	}
+	fmt.Println(s.Find(todo))
+	s.Print()
+
+	// Output:
+	// 6 ' ' "one\nskip\nTODO: two"
+	// 21 ' ' "two"
+	// false
+	// 21 ' ' "two"
+}
+
+func ExampleR_ReplaceAll() {
+	s := new(scan.R)
+	s.B = []byte("TODO: one\nskip\nTODO: two")
+
+	out := s.ReplaceAll(regexp.MustCompile(`TODO: (\p{L}+)`),
+		func(match string) string { return strings.ToUpper(match[6:]) })
+	fmt.Printf("%q\n", out)
+	fmt.Printf("%q\n", s.B)
+
+	// Output:
+	// "ONE\nskip\nTWO"
+	// "TODO: one\nskip\nTODO: two"
+}
+
+func ExampleR_Documents() {
+	s := new(scan.R)
+	s.B = []byte("a: 1\n---\nb: 2\n---\n")
+
+	for _, doc := range s.Documents(regexp.MustCompile(`(?m)^---\n`)) {
+		fmt.Printf("%v %q\n", doc, doc.Text(s.B))
+	}
+
+	// Output:
+	// {0 5} "a: 1\n"
+	// {9 14} "b: 2\n"
+	// {18 18} ""
+}
+
+func ExampleR_Find_literal() {
+	s := new(scan.R)
+	s.B = []byte("skip over all of this 👿 until END and stop")
+
+	fmt.Println(s.Find(regexp.MustCompile(`END`)))
+	s.Print()
+	fmt.Println(s.Find(regexp.MustCompile(`MISSING`)))
+
+	// Output:
+	// true
+	// 33 ' ' "END and stop"
+	// false
+}
@@ -0,0 +1,517 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package g4 imports the lexer rules of ANTLR4 (.g4) grammars as
+a scan.Lexer so that the huge library of existing grammars can be
+reused. Since scan has no parser expressions only lexer rules are
+converted (into regular expressions); the names of any parser rules are
+recorded but otherwise ignored. The supported subset includes literals
+(with escapes), character sets, ranges, the wildcard, negation,
+grouping, alternation, the ?, *, and + suffixes (including non-greedy),
+fragment rules (inlined where referenced), and the skip and channel
+commands (both of which skip the token). Actions, predicates, modes,
+imports, and other commands are reported as errors. The Lexer uses the
+longest match (as ANTLR does) with ties going to the first rule, and
+so does each rule expression unless it contains non-greedy operators.
+*/
+package g4
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rwxrob/scan"
+)
+
+// Grammar is the result of an Import.
+type Grammar struct {
+	Name   string     // from the grammar declaration
+	Lexer  scan.Lexer // one Rule per (non-fragment) lexer rule
+	Types  []string   // lexer rule names, index is Rule.Type
+	Parser []string   // names of parser rules (ignored)
+}
+
+// token types of the g4 language itself
+const (
+	tSpace = iota
+	tName
+	tLit
+	tSet
+	tArrow
+	tRange
+	tBlock
+	tPunct
+)
+
+var lexer = scan.Lexer{Longest: true, Rules: []scan.Rule{
+	{Type: tSpace, Re: regexp.MustCompile(`\s+|//[^\n]*|/\*(?s:.*?)\*/`), Skip: true},
+	{Type: tName, Re: regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)},
+	{Type: tLit, Re: regexp.MustCompile(`'(?:\\.|[^'\\])*'`)},
+	{Type: tSet, Re: regexp.MustCompile(`\[(?:\\.|[^\]\\])*\]`)},
+	{Type: tArrow, Re: regexp.MustCompile(`->`)},
+	{Type: tRange, Re: regexp.MustCompile(`\.\.`)},
+	{Type: tBlock, Re: regexp.MustCompile(`\{(?:[^{}]|\{[^{}]*\})*\}`)},
+	{Type: tPunct, Re: regexp.MustCompile(`[:;|()?*+~.,=@#<>]`)},
+}}
+
+type rule struct {
+	name     string
+	fragment bool
+	toks     []scan.Token // body between ':' and ';'
+}
+
+// Import reads the ANTLR4 grammar source and returns the Grammar with
+// the Lexer built from its lexer rules. Errors include the position
+// within the source.
+func Import(src []byte) (*Grammar, error) {
+	s := new(scan.R)
+	s.Buffer(src)
+	toks, err := lexer.Lex(s)
+	if err != nil {
+		return nil, err
+	}
+	t := &scan.T{Toks: toks}
+	g := new(Grammar)
+	var rules []*rule
+	defs := map[string]*rule{}
+
+	for !t.End() {
+		switch {
+
+		case t.Peek(tName, tName, tName, tPunct) && t.Toks[t.P+1].Text == "grammar":
+			t.Scan()
+			fallthrough
+		case t.Peek(tName, tName, tPunct) && t.Toks[t.P].Text == "grammar":
+			t.Scan()
+			t.Scan()
+			g.Name = t.T.Text
+			t.Scan()
+
+		case t.Peek(tName, tBlock) && in(t.Toks[t.P].Text, "options", "tokens", "channels"):
+			t.Scan()
+			t.Scan()
+
+		case t.Peek(tPunct) && t.Toks[t.P].Text == "@":
+			for t.Scan() && t.T.Type != tBlock {
+			}
+
+		case t.Peek(tName) && in(t.Toks[t.P].Text, "import", "mode"):
+			t.Error("unsupported: %v", t.Toks[t.P].Text)
+			return nil, t.Errors[0]
+
+		case t.Peek(tName, tName, tPunct) && t.Toks[t.P].Text == "fragment",
+			t.Peek(tName, tPunct):
+			r := new(rule)
+			if t.Toks[t.P].Text == "fragment" {
+				r.fragment = true
+				t.Scan()
+			}
+			t.Scan()
+			r.name = t.T.Text
+			if !t.In(tPunct) || t.Toks[t.P].Text != ":" {
+				t.Error("expected ':'")
+				return nil, t.Errors[0]
+			}
+			t.Scan()
+			for t.Scan() && !(t.T.Type == tPunct && t.T.Text == ";") {
+				r.toks = append(r.toks, t.T)
+			}
+			if t.T.Text != ";" {
+				t.Error("expected ';'")
+				return nil, t.Errors[0]
+			}
+			if unicode.IsUpper(rune(r.name[0])) {
+				rules = append(rules, r)
+				defs[r.name] = r
+				continue
+			}
+			g.Parser = append(g.Parser, r.name)
+
+		default:
+			t.Error("unexpected %q", t.Toks[t.P].Text)
+			return nil, t.Errors[0]
+		}
+	}
+
+	c := &compiler{defs: defs, done: map[string]compiled{}, busy: map[string]bool{}}
+	for _, r := range rules {
+		if r.fragment {
+			continue
+		}
+		re, skip, err := c.compile(r)
+		if err != nil {
+			return nil, err
+		}
+		x, err := regexp.Compile(`^(?:` + re + `)`)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", r.name, err)
+		}
+		if !lazy(re) {
+			x.Longest()
+		}
+		g.Lexer.Rules = append(g.Lexer.Rules,
+			scan.Rule{Type: len(g.Types), Re: x, Skip: skip})
+		g.Types = append(g.Types, r.name)
+	}
+	g.Lexer.Longest = true
+	return g, nil
+}
+
+// lazy returns true if the expression contains any non-greedy
+// operators (which leftmost-longest matching would ignore).
+func lazy(re string) bool {
+	for _, op := range []string{`)??`, `)*?`, `)+?`} {
+		if strings.Contains(re, op) {
+			return true
+		}
+	}
+	return false
+}
+
+func in(s string, list ...string) bool {
+	for _, v := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+type compiler struct {
+	defs map[string]*rule
+	done map[string]compiled // compiled rules
+	busy map[string]bool     // rules being compiled (cycles)
+}
+
+// compiled is the regular expression of a rule and whether its command
+// skips the token.
+type compiled struct {
+	re   string
+	skip bool
+}
+
+// compile returns the regular expression for the rule and whether its
+// command skips the token.
+func (c *compiler) compile(r *rule) (string, bool, error) {
+	if done, has := c.done[r.name]; has {
+		return done.re, done.skip, nil
+	}
+	if c.busy[r.name] {
+		return "", false, fmt.Errorf("recursive rule not supported: %v", r.name)
+	}
+	c.busy[r.name] = true
+	defer delete(c.busy, r.name)
+
+	toks := r.toks
+	var skip bool
+	for i, tok := range toks {
+		if tok.Type != tArrow {
+			continue
+		}
+		for _, cmd := range toks[i+1:] {
+			switch {
+			case cmd.Type == tName && in(cmd.Text, "skip", "channel"):
+				skip = true
+			case cmd.Type == tName && cmd.Text == "HIDDEN",
+				cmd.Type == tPunct && in(cmd.Text, "(", ")", ","):
+			default:
+				return "", false, fmt.Errorf("%v: unsupported command at %v",
+					r.name, cmd.Pos)
+			}
+		}
+		toks = toks[:i]
+		break
+	}
+
+	p := &parser{c: c, t: &scan.T{Toks: toks}}
+	re, err := p.alts()
+	if err == nil && !p.t.End() {
+		err = fmt.Errorf("%v: unexpected %q at %v", r.name,
+			p.t.Toks[p.t.P].Text, p.t.Toks[p.t.P].Pos)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	c.done[r.name] = compiled{re, skip}
+	return re, skip, nil
+}
+
+// parser converts the tokens of a single rule body.
+type parser struct {
+	c *compiler
+	t *scan.T
+}
+
+func (p *parser) punct(text string) bool {
+	return p.t.In(tPunct) && p.t.Toks[p.t.P].Text == text
+}
+
+func (p *parser) errorf(form string, a ...any) error {
+	tok := p.t.T
+	if !p.t.End() {
+		tok = p.t.Toks[p.t.P]
+	}
+	return fmt.Errorf("%v at %v", fmt.Sprintf(form, a...), tok.Pos)
+}
+
+// alts <- seq ('|' seq)*
+func (p *parser) alts() (string, error) {
+	var list []string
+	for {
+		seq, err := p.seq()
+		if err != nil {
+			return "", err
+		}
+		list = append(list, seq)
+		if !p.punct("|") {
+			break
+		}
+		p.t.Scan()
+	}
+	if len(list) == 1 {
+		return list[0], nil
+	}
+	return `(?:` + strings.Join(list, `|`) + `)`, nil
+}
+
+// seq <- (atom suffix?)*
+func (p *parser) seq() (string, error) {
+	var buf strings.Builder
+	for !p.t.End() && !p.punct("|") && !p.punct(")") {
+		a, err := p.atom()
+		if err != nil {
+			return "", err
+		}
+		re := a.re
+		for _, op := range []string{"?", "*", "+"} {
+			if p.punct(op) {
+				p.t.Scan()
+				re = `(?:` + re + `)` + op
+				if p.punct("?") {
+					p.t.Scan()
+					re += "?"
+				}
+				break
+			}
+		}
+		buf.WriteString(re)
+	}
+	return buf.String(), nil
+}
+
+// item is the expression of an atom and its character class contents
+// (without brackets) if it can be negated (empty otherwise).
+type item struct {
+	re  string
+	cls string
+}
+
+// atom <- Lit ('..' Lit)? / Set / '.' / '~' atom / '(' alts ')' / Name
+func (p *parser) atom() (item, error) {
+	t := p.t
+	switch {
+
+	case t.In(tLit):
+		t.Scan()
+		lo, err := unquote(t.T.Text[1:len(t.T.Text)-1], false)
+		if err != nil {
+			return item{}, p.errorf("%v", err)
+		}
+		if t.In(tRange) {
+			t.Scan()
+			if !t.In(tLit) {
+				return item{}, p.errorf("expected literal")
+			}
+			t.Scan()
+			hi, err := unquote(t.T.Text[1:len(t.T.Text)-1], false)
+			if err != nil {
+				return item{}, p.errorf("%v", err)
+			}
+			if len([]rune(lo)) != 1 || len([]rune(hi)) != 1 {
+				return item{}, p.errorf("range requires single characters")
+			}
+			cls := esc([]rune(lo)[0]) + `-` + esc([]rune(hi)[0])
+			return item{`[` + cls + `]`, cls}, nil
+		}
+		if rs := []rune(lo); len(rs) == 1 {
+			return item{regexp.QuoteMeta(lo), esc(rs[0])}, nil
+		}
+		return item{regexp.QuoteMeta(lo), ""}, nil
+
+	case t.In(tSet):
+		t.Scan()
+		cls, err := set(t.T.Text[1 : len(t.T.Text)-1])
+		if err != nil {
+			return item{}, p.errorf("%v", err)
+		}
+		return item{`[` + cls + `]`, cls}, nil
+
+	case p.punct("."):
+		t.Scan()
+		return item{`(?s:.)`, ""}, nil
+
+	case p.punct("~"):
+		t.Scan()
+		a, err := p.atom()
+		if err != nil {
+			return item{}, err
+		}
+		if a.cls == "" {
+			return item{}, p.errorf("cannot negate %v", a.re)
+		}
+		return item{`[^` + a.cls + `]`, ""}, nil
+
+	case p.punct("("):
+		t.Scan()
+		mark := t.P
+		var cls []string
+		for {
+			a, err := p.atom()
+			if err != nil || a.cls == "" || !(p.punct("|") || p.punct(")")) {
+				cls = nil
+				break
+			}
+			cls = append(cls, a.cls)
+			if p.punct(")") {
+				break
+			}
+			t.Scan()
+		}
+		t.P = mark
+		re, err := p.alts()
+		if err != nil {
+			return item{}, err
+		}
+		if !p.punct(")") {
+			return item{}, p.errorf("expected ')'")
+		}
+		t.Scan()
+		return item{`(?:` + re + `)`, strings.Join(cls, "")}, nil
+
+	case t.In(tName):
+		t.Scan()
+		r, has := p.c.defs[t.T.Text]
+		if !has {
+			return item{}, p.errorf("undefined lexer rule %v", t.T.Text)
+		}
+		re, _, err := p.c.compile(r)
+		if err != nil {
+			return item{}, err
+		}
+		return item{`(?:` + re + `)`, ""}, nil
+
+	case t.In(tBlock):
+		return item{}, p.errorf("actions and predicates not supported")
+	}
+
+	if t.End() {
+		return item{}, p.errorf("unexpected end of rule")
+	}
+	return item{}, p.errorf("unexpected %q", t.Toks[t.P].Text)
+}
+
+// esc returns the rune as a regular expression escape usable both in
+// and out of character classes.
+func esc(r rune) string { return fmt.Sprintf(`\x{%x}`, r) }
+
+// unquote converts the ANTLR escapes of a literal (or set when inset is
+// true, which adds \] and \-) into the runes they represent.
+func unquote(s string, inset bool) (string, error) {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("incomplete escape")
+		}
+		switch c := s[i]; c {
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'f':
+			buf.WriteByte('\f')
+		case '\\', '\'':
+			buf.WriteByte(c)
+		case ']', '-':
+			if !inset {
+				return "", fmt.Errorf("invalid escape \\%c", c)
+			}
+			buf.WriteByte(c)
+		case 'u':
+			hex := ""
+			switch {
+			case i+1 < len(s) && s[i+1] == '{':
+				end := strings.IndexByte(s[i:], '}')
+				if end < 0 {
+					return "", fmt.Errorf("incomplete unicode escape")
+				}
+				hex = s[i+2 : i+end]
+				i += end
+			case i+4 < len(s):
+				hex = s[i+1 : i+5]
+				i += 4
+			default:
+				return "", fmt.Errorf("incomplete unicode escape")
+			}
+			n, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", hex)
+			}
+			buf.WriteRune(rune(n))
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", c)
+		}
+	}
+	return buf.String(), nil
+}
+
+// set converts the contents of an ANTLR character set into those of
+// a regular expression character class.
+func set(s string) (string, error) {
+	var buf strings.Builder
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '-' && i > 0 && i < len(rs)-1:
+			buf.WriteByte('-')
+			continue
+		case r == '\\':
+			end := i + 2
+			if end <= len(rs) && rs[i+1] == 'u' {
+				switch {
+				case end < len(rs) && rs[end] == '{':
+					for end < len(rs) && rs[end] != '}' {
+						end++
+					}
+					end++
+				default:
+					end += 4
+				}
+			}
+			if end > len(rs) {
+				return "", fmt.Errorf("incomplete escape in set")
+			}
+			u, err := unquote(string(rs[i:end]), true)
+			if err != nil {
+				return "", err
+			}
+			r = []rune(u)[0]
+			i = end - 1
+		}
+		buf.WriteString(esc(r))
+	}
+	return buf.String(), nil
+}
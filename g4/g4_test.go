@@ -0,0 +1,102 @@
+package g4_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+	"github.com/rwxrob/scan/g4"
+)
+
+func ExampleImport() {
+	g, err := g4.Import([]byte(`
+grammar Expr;
+
+// parser rules are recorded but ignored
+expr : expr ('*'|'/') expr | INT | ID ;
+
+ID     : LETTER (LETTER | DIGIT)* ;
+INT    : DIGIT+ ;
+FLOAT  : DIGIT+ '.' DIGIT* ;
+OP     : [-+*/=] | '**' ;
+STR    : '"' ~["\r\n]* '"' ;
+WS     : [ \t\r\n]+ -> skip ;
+COMMENT: '/*' .*? '*/' -> channel(HIDDEN) ;
+
+fragment LETTER : 'a'..'z' | 'A'..'Z' | '_' ;
+fragment DIGIT  : [0-9] ;
+`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(g.Name, g.Types, g.Parser)
+
+	s := new(scan.R)
+	s.Buffer(`x1 = 3.14 ** /* note */ "hi" + 42`)
+	toks, err := g.Lexer.Lex(s)
+	for _, t := range toks {
+		fmt.Printf("%v %q\n", g.Types[t.Type], t.Text)
+	}
+	fmt.Println(err)
+
+	// Output:
+	// Expr [ID INT FLOAT OP STR WS COMMENT] [expr]
+	// ID "x1"
+	// OP "="
+	// FLOAT "3.14"
+	// OP "**"
+	// STR "\"hi\""
+	// OP "+"
+	// INT "42"
+	// <nil>
+}
+
+func ExampleImport_unsupported() {
+	_, err := g4.Import([]byte(`
+lexer grammar L;
+ID : [a-z]+ { System.out.println("id"); } ;
+`))
+	fmt.Println(err)
+
+	_, err = g4.Import([]byte(`
+lexer grammar L;
+A : 'a' B ;
+B : 'b' A ;
+`))
+	fmt.Println(err)
+
+	// Output:
+	// actions and predicates not supported at U+007B '{' 3,13-13 (31-31)
+	// recursive rule not supported: A
+}
+
+func ExampleImport_truncated() {
+	for _, src := range []string{"X : ( ;", "X : ~ ;", "X : .( ;"} {
+		_, err := g4.Import([]byte(src))
+		fmt.Println(err)
+	}
+
+	// Output:
+	// expected ')' at U+0028 '(' 1,5-5 (5-5)
+	// unexpected end of rule at U+007E '~' 1,5-5 (5-5)
+	// expected ')' at U+0028 '(' 1,6-6 (6-6)
+}
+
+func ExampleImport_forward() {
+	g, err := g4.Import([]byte(`
+lexer grammar L;
+B : A 'b' ;
+A : ' ' -> skip ;
+`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for i, r := range g.Lexer.Rules {
+		fmt.Println(g.Types[i], r.Skip)
+	}
+
+	// Output:
+	// B false
+	// A true
+}
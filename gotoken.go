@@ -0,0 +1,142 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"bytes"
+	"go/token"
+	"unicode/utf8"
+)
+
+// lineStarts returns the byte offset of the beginning of every line in
+// the buffer honoring NewLine (longest sequence wins).
+func (s R) lineStarts() []int {
+	nls := s.newlines()
+	lines := []int{0}
+	for i := 0; i < len(s.B); {
+		var nlen int
+		for _, nl := range nls {
+			if len(nl) > nlen && bytes.HasPrefix(s.B[i:], []byte(nl)) {
+				nlen = len(nl)
+			}
+		}
+		if nlen == 0 {
+			i++
+			continue
+		}
+		i += nlen
+		if i < len(s.B) {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
+
+// AddFile registers the buffer (B) with the go/token.FileSet under the
+// given name including the offsets of every line (see NewLine) so that
+// scan-based mini-languages can reuse the position plumbing of the Go
+// toolchain (go/scanner.ErrorList, go/printer, and such). When the
+// buffer has several Files (see Open) each is added as alternative
+// position information (see token.File.AddLineColumnInfo) so that
+// positions report the name, line, and column within their File.
+func (s R) AddFile(fset *token.FileSet, name string) *token.File {
+	f := fset.AddFile(name, -1, len(s.B))
+	f.SetLines(s.lineStarts())
+	for _, file := range s.Files {
+		f.AddLineColumnInfo(file.Off, file.Name, 1, 1)
+	}
+	return f
+}
+
+// TokenPos converts the Position into a token.Pos within the file (f)
+// as returned by AddFile. Since BufByte points *after* the rune the
+// token.Pos points to the beginning of the rune instead. The zero
+// Position returns token.NoPos.
+func (p Position) TokenPos(f *token.File) token.Pos {
+	if p.BufByte == 0 {
+		return token.NoPos
+	}
+	return f.Pos(p.BufByte - utf8.RuneLen(p.Rune))
+}
+
+// TokenPosition converts the Position into a token.Position for the
+// named file. Offset is that of the beginning of the rune and Column is
+// in bytes (LByte) as with the Go toolchain.
+func (p Position) TokenPosition(filename string) token.Position {
+	if p.BufByte == 0 {
+		return token.Position{Filename: filename}
+	}
+	return token.Position{
+		Filename: filename,
+		Offset:   p.BufByte - utf8.RuneLen(p.Rune),
+		Line:     p.Line,
+		Column:   p.LByte,
+	}
+}
+
+// TokenPositions returns the Position for each token.Pos within the
+// file (f) as returned by AddFile (all in one pass, see Positions).
+func (s R) TokenPositions(f *token.File, p ...token.Pos) []Position {
+	offs := make([]int, len(p))
+	for i, v := range p {
+		if v.IsValid() {
+			offs[i] = f.Offset(v) + 1
+		}
+	}
+	return s.Positions(offs...)
+}
+
+// AnalysisDiagnostic has the same shape as the Diagnostic of the
+// golang.org/x/tools/go/analysis package (without depending on it) so
+// that scan-based linters can slot directly into existing analysis
+// drivers by copying the fields.
+type AnalysisDiagnostic struct {
+	Pos            token.Pos
+	End            token.Pos // optional
+	Category       string    // optional
+	Message        string
+	SuggestedFixes []SuggestedFix // optional
+}
+
+// SuggestedFix has the same shape as analysis.SuggestedFix.
+type SuggestedFix struct {
+	Message   string
+	TextEdits []TextEdit
+}
+
+// TextEdit has the same shape as analysis.TextEdit.
+type TextEdit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText []byte
+}
+
+// TextEdits converts the Edits into TextEdits within the file (f) as
+// returned by AddFile.
+func (e Edits) TextEdits(f *token.File) []TextEdit {
+	list := make([]TextEdit, len(e))
+	for i, ed := range e {
+		list[i] = TextEdit{f.Pos(ed.B), f.Pos(ed.E), []byte(ed.Text)}
+	}
+	return list
+}
+
+// AnalysisDiagnostics returns an AnalysisDiagnostic for each of the
+// Errors (see Errs) within the file (f) as returned by AddFile. If fix
+// is not nil it is called with each Error and any Edits it returns are
+// added as a SuggestedFix with the message.
+func (s R) AnalysisDiagnostics(f *token.File, fix func(e Error) (string, Edits)) []AnalysisDiagnostic {
+	errs := s.Errs()
+	list := make([]AnalysisDiagnostic, len(errs))
+	for i, e := range errs {
+		d := AnalysisDiagnostic{Pos: e.Pos.TokenPos(f), Message: e.Msg}
+		if fix != nil {
+			if msg, edits := fix(e); len(edits) > 0 {
+				d.SuggestedFixes = []SuggestedFix{{msg, edits.TextEdits(f)}}
+			}
+		}
+		list[i] = d
+	}
+	return list
+}
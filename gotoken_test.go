@@ -0,0 +1,100 @@
+package scan_test
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_AddFile() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand a 👿\r\nand yet another")
+
+	fset := token.NewFileSet()
+	f := s.AddFile(fset, "sample.txt")
+	fmt.Println(f.LineCount())
+
+	pos := s.Positions(12, 19, 27)
+	for _, p := range pos {
+		tp := p.TokenPos(f)
+		fmt.Println(fset.Position(tp), p.TokenPosition("sample.txt"))
+	}
+
+	for _, p := range s.TokenPositions(f, pos[1].TokenPos(f), token.NoPos) {
+		p.Print()
+	}
+
+	// Output:
+	// 3
+	// sample.txt:2:3 sample.txt:2:3
+	// sample.txt:2:7 sample.txt:2:7
+	// sample.txt:3:6 sample.txt:3:6
+	// U+1F47F '👿' 2,7-7 (16-19)
+	// U+0000 '\x00' 0,0-0 (0-0)
+}
+
+func ExampleR_AnalysisDiagnostics() {
+	s := new(scan.R)
+	s.Buffer("let x = 1\nlet y = 2;;\n")
+	for s.Scan() {
+		if s.R == ';' && s.Peek(";") {
+			s.Errors = append(s.Errors, scan.Error{P: s.P + 1, Msg: "extra semicolon"})
+		}
+	}
+
+	fset := token.NewFileSet()
+	f := s.AddFile(fset, "x.let")
+	diags := s.AnalysisDiagnostics(f, func(e scan.Error) (string, scan.Edits) {
+		var edits scan.Edits
+		edits.AddReplacement(scan.Span{B: e.P - 1, E: e.P}, "")
+		return "remove it", edits
+	})
+	for _, d := range diags {
+		fmt.Println(fset.Position(d.Pos), d.Message)
+		for _, fix := range d.SuggestedFixes {
+			for _, ed := range fix.TextEdits {
+				fmt.Println(fix.Message, fset.Position(ed.Pos), fset.Position(ed.End), ed.NewText)
+			}
+		}
+	}
+
+	// Output:
+	// x.let:2:11 extra semicolon
+	// remove it x.let:2:11 x.let:2:12 []
+}
+
+func ExampleR_AddFile_files() {
+	s := new(scan.R)
+	if err := s.BufferAll("head\n", "one\ntwo", "x"); err != nil {
+		fmt.Println(err)
+	}
+
+	fset := token.NewFileSet()
+	f := s.AddFile(fset, "all")
+	for _, p := range s.Positions(2, 7, 12, 13) {
+		fmt.Println(fset.Position(p.TokenPos(f)), p)
+	}
+
+	// Output:
+	// #1:1:2 #1 U+0065 'e' 1,2-2 (2-2)
+	// #2:1:2 #2 U+006E 'n' 1,2-2 (7-7)
+	// #2:2:3 #2 U+006F 'o' 2,3-3 (12-12)
+	// #3:1:1 #3 U+0078 'x' 1,1-1 (13-13)
+}
+
+func ExampleR_AddFile_normalized() {
+	s := new(scan.R)
+	s.Buffer("a\r\n\r\nb")
+	s.NormalizeNewLines()
+	s.Track = true
+	for s.Scan() {
+	}
+
+	fset := token.NewFileSet()
+	f := s.AddFile(fset, "x")
+	fmt.Println(fset.Position(s.Pos().TokenPos(f)), s.Orig(s.PP))
+
+	// Output:
+	// x:3:1 5
+}
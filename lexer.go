@@ -0,0 +1,234 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"iter"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule is a single type of Token and the expression matching it.
+type Rule struct {
+	Type int            // of Token, defined by application
+	Re   *regexp.Regexp // must match at the current position (see Tokens)
+	Skip bool           // match but do not produce (spaces, comments)
+
+	// Value (if set) converts the matched text into a typed value
+	// (strconv.Atoi, time.Parse, and such) stored in Token.V. If it
+	// returns an error (or panics) the scan fails with it at the Token
+	// Position.
+	Value func(text string) (any, error)
+}
+
+// Lexer produces Tokens of different types by trying each of its Rules
+// in order (priority) at the current position of a scanner with the
+// first match winning (or, when Longest is set, the longest match with
+// ties going to the first). This allows the classic lex/parse split
+// (see T) using the same regular expressions used with everything else.
+type Lexer struct {
+	Rules   []Rule
+	Longest bool      // longest match wins (as with lex and ANTLR)
+	Cover   *Coverage // counts Rule attempts and matches when set
+
+	// MaxTokens and MaxBytes (when greater than zero) limit the number
+	// of Tokens produced and the total bytes of their Text so that
+	// services are protected from adversarial input (see Tokens).
+	MaxTokens int
+	MaxBytes  int
+}
+
+// Tokens returns an iterator that lazily produces a Token (including
+// its Type and Position) for each match of the Rules from the current
+// position (P) of the scanner (s) advancing past each as it goes.
+// Iteration ends at the end of the buffer or when no Rule matches (or
+// only an empty string) in which case the Error (with the Position of
+// the unexpected rune) is added to s.Errors and produced with an empty
+// Token as the final pair. The same is true when a Rule Value fails or
+// a budget (MaxTokens, MaxBytes) is exceeded (leaving the scanner before
+// the Token). Rule expressions not already beginning with ^ are matched
+// with an anchored copy (compiled once per call) so that a Rule that
+// does not match never searches the rest of the buffer.
+func (l Lexer) Tokens(s *R) iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		res := make([]*regexp.Regexp, len(l.Rules))
+		for i, rule := range l.Rules {
+			res[i] = rule.Re
+			if !strings.HasPrefix(rule.Re.String(), "^") {
+				res[i] = regexp.MustCompile(`^(?:` + rule.Re.String() + `)`)
+			}
+		}
+		var c counter
+		c.sync(s)
+		var ntoks, nbytes int
+		base := s.Base
+		for !s.End() {
+			rule, n := l.match(s, res)
+			if s.Base != base { // stream window moved (see Stream)
+				base = s.Base
+				c = counter{}
+				c.sync(s)
+			}
+			if n <= 0 {
+				r, _ := utf8.DecodeRune(s.B[s.P:])
+				err := Error{
+					P:   s.P + 1,
+					Pos: c.to(s.B, s.P+1),
+					Msg: msg("unexpected %q", r),
+				}
+				s.Errors = append(s.Errors, err)
+				yield(Token{}, err)
+				return
+			}
+			t := Token{Span: Span{s.P, s.P + n}, Type: rule.Type}
+			t.Text = t.Span.Text(s.B)
+			t.Pos = c.to(s.B, t.E)
+			if rule.Value != nil {
+				var v any
+				var err error
+				if perr := contain("Rule.Value", func() { v, err = rule.Value(t.Text) }); perr != nil {
+					err = perr
+				}
+				if err != nil {
+					err := Error{P: t.B + 1, Pos: t.Pos, Msg: err.Error()}
+					s.Errors = append(s.Errors, err)
+					yield(Token{}, err)
+					return
+				}
+				t.V = v
+			}
+			if !rule.Skip {
+				ntoks, nbytes = ntoks+1, nbytes+len(t.Text)
+				var over string
+				switch {
+				case l.MaxTokens > 0 && ntoks > l.MaxTokens:
+					over = msg("more than %v tokens", l.MaxTokens)
+				case l.MaxBytes > 0 && nbytes > l.MaxBytes:
+					over = msg("more than %v bytes of tokens", l.MaxBytes)
+				}
+				if over != "" {
+					err := Error{P: t.B + 1, Pos: t.Pos, Msg: over}
+					s.Errors = append(s.Errors, err)
+					yield(Token{}, err)
+					return
+				}
+			}
+			s.goTo(t.E)
+			if s.breakOn != nil && s.breakOn[t.Type] {
+				s.breakpoint()
+			}
+			if rule.Skip {
+				continue
+			}
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// match returns the Rule matching at the current position and the
+// length of the match (0 if none) honoring Longest using the anchored
+// expressions (res) of the Rules.
+func (l Lexer) match(s *R, res []*regexp.Regexp) (Rule, int) {
+	best := -1
+	var max int
+	s.ahead(s.window)
+	for i := range l.Rules {
+		if l.Cover != nil {
+			l.Cover.add(&l.Cover.Attempts, i)
+		}
+		n := -1
+		if loc := res[i].FindIndex(s.B[s.P:]); loc != nil {
+			n = loc[1]
+		}
+		if n > max {
+			best, max = i, n
+			if !l.Longest {
+				break
+			}
+		}
+	}
+	if best < 0 {
+		return Rule{}, 0
+	}
+	if l.Cover != nil {
+		l.Cover.add(&l.Cover.Matches, best)
+	}
+	return l.Rules[best], max
+}
+
+// Lex returns all the Tokens (see Tokens) stopping at the first error.
+func (l Lexer) Lex(s *R) ([]Token, error) {
+	var toks []Token
+	for t, err := range l.Tokens(s) {
+		if err != nil {
+			return toks, err
+		}
+		toks = append(toks, t)
+	}
+	return toks, nil
+}
+
+// ParseLexer returns the Lexer (and the names of its Token types) from
+// text so that rules can live in configuration files and be loaded at
+// runtime without recompiling. Each line is the name of the Token type
+// followed by white space and the regular expression (the rest of the
+// line trimmed of white space) that matches it. Names beginning with
+// a dash are Skip rules (with the dash dropped from the name). Rules
+// with the same name share a Type, which is the index of the name in
+// the names returned. Blank lines and those beginning with # are
+// ignored and a line containing only %longest sets Longest. Errors
+// include the line number.
+func ParseLexer(text string) (Lexer, []string, error) {
+	var l Lexer
+	var names []string
+	types := map[string]int{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case len(line) == 0 || line[0] == '#':
+			continue
+		case line == "%longest":
+			l.Longest = true
+			continue
+		}
+		n := strings.IndexAny(line, " \t")
+		if n < 0 {
+			return Lexer{}, nil, fmt.Errorf("line %v: expected name and expression", i+1)
+		}
+		name, expr := line[:n], strings.TrimSpace(line[n:])
+		rule := Rule{Skip: name[0] == '-'}
+		name = strings.TrimPrefix(name, "-")
+		if name == "" {
+			return Lexer{}, nil, fmt.Errorf("line %v: expected name and expression", i+1)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return Lexer{}, nil, fmt.Errorf("line %v: %v", i+1, err)
+		}
+		rule.Re = re
+		t, has := types[name]
+		if !has {
+			t = len(names)
+			types[name] = t
+			names = append(names, name)
+		}
+		rule.Type = t
+		l.Rules = append(l.Rules, rule)
+	}
+	return l, names, nil
+}
+
+// MustParseLexer is the same as ParseLexer but panics on error (see
+// regexp.MustCompile) and is meant for package variables.
+func MustParseLexer(text string) (Lexer, []string) {
+	l, names, err := ParseLexer(text)
+	if err != nil {
+		panic(err)
+	}
+	return l, names
+}
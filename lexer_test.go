@@ -0,0 +1,149 @@
+package scan_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/scan"
+)
+
+const (
+	Ident = iota + 1
+	Number
+	Op
+)
+
+var lexer = scan.Lexer{Rules: []scan.Rule{
+	{Re: regexp.MustCompile(`\s+`), Skip: true},
+	{Type: Number, Re: regexp.MustCompile(`\d+`)},
+	{Type: Ident, Re: regexp.MustCompile(`\p{L}[\p{L}\d]*`)},
+	{Type: Op, Re: regexp.MustCompile(`[-+*/=]`)},
+}}
+
+func ExampleLexer() {
+	s := new(scan.R)
+	s.B = []byte("x = 42\n  * y2 $ 1")
+
+	toks, err := lexer.Lex(s)
+	for _, t := range toks {
+		fmt.Println(t.Type, t.Span, fmt.Sprintf("%q", t.Text), t.Pos)
+	}
+	fmt.Println(err, err.(scan.Error).P)
+
+	// Output:
+	// 1 {0 1} "x" U+0078 'x' 1,1-1 (1-1)
+	// 3 {2 3} "=" U+003D '=' 1,3-3 (3-3)
+	// 2 {4 6} "42" U+0034 '4' 1,5-5 (5-5)
+	// 3 {9 10} "*" U+002A '*' 2,3-3 (10-10)
+	// 1 {11 13} "y2" U+0079 'y' 2,5-5 (12-12)
+	// unexpected '$' at U+0024 '$' 2,8-8 (15-15) 15
+}
+
+func ExampleRule_Value() {
+	lx := scan.Lexer{Rules: []scan.Rule{
+		{Re: regexp.MustCompile(`\s+`), Skip: true},
+		{Type: Number, Re: regexp.MustCompile(`\d+`),
+			Value: func(text string) (any, error) { return strconv.Atoi(text) }},
+	}}
+
+	s := new(scan.R)
+	s.B = []byte("1 22 99999999999999999999")
+	toks, err := lx.Lex(s)
+	for _, t := range toks {
+		fmt.Printf("%v %T\n", t.V, t.V)
+	}
+	fmt.Println(err)
+
+	// Output:
+	// 1 int
+	// 22 int
+	// strconv.Atoi: parsing "99999999999999999999": value out of range at U+0039 '9' 1,6-6 (6-6)
+}
+
+func ExampleR_BreakOn() {
+	s := new(scan.R)
+	s.B = []byte("x = 42 + y")
+	s.Break = func(s *scan.R) { fmt.Print("break: "); s.Print() }
+	s.BreakOn(Op)
+	lexer.Lex(s)
+
+	// Output:
+	// break: 3 '=' " 42 + y"
+	// break: 8 '+' " y"
+}
+
+func ExampleParseLexer() {
+	l, names, err := scan.ParseLexer(`
+# rules loaded at runtime
+%longest
+-space  \s+
+Num     \d+(\.\d+)?
+Word    \p{L}+
+Op      [-+*/=]
+Op      \*\*
+`)
+	fmt.Println(names, l.Longest, err)
+
+	s := new(scan.R)
+	s.Buffer("x = 2 ** 1.5")
+	for t := range l.Tokens(s) {
+		fmt.Println(names[t.Type], t.Text)
+	}
+
+	_, _, err = scan.ParseLexer("Num \\d+\nOp [-+")
+	fmt.Println(err)
+
+	// Output:
+	// [space Num Word Op] true <nil>
+	// Word x
+	// Op =
+	// Num 2
+	// Op **
+	// Num 1.5
+	// line 2: error parsing regexp: missing closing ]: `[-+`
+}
+
+func ExampleLexer_MaxTokens() {
+	l := lexer
+	l.MaxTokens = 3
+
+	s := new(scan.R)
+	s.Buffer("a = b + c")
+	toks, err := l.Lex(s)
+	fmt.Println(len(toks), err, s.P)
+
+	l.MaxTokens, l.MaxBytes = 0, 8
+	s.Buffer("short = muchlonger")
+	toks, err = l.Lex(s)
+	fmt.Println(len(toks), err)
+
+	// Output:
+	// 3 more than 3 tokens at U+002B '+' 1,7-7 (7-7) 6
+	// 2 more than 8 bytes of tokens at U+006D 'm' 1,9-9 (9-9)
+}
+
+// The MB/s reported should be about the same for every size since
+// lexing is linear (rules that do not match never search ahead).
+func BenchmarkLexer_Lex(b *testing.B) {
+	words := scan.Lexer{Rules: []scan.Rule{
+		{Type: Number, Re: regexp.MustCompile(`[0-9]+`)},
+		{Type: Ident, Re: regexp.MustCompile(`[a-z]+`)},
+		{Re: regexp.MustCompile(`\s+`), Skip: true},
+	}}
+	for _, n := range []int{1000, 4000, 16000} {
+		buf := []byte(strings.Repeat("abc ", n))
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			s := new(scan.R)
+			b.SetBytes(int64(len(buf)))
+			for range b.N {
+				s.Buffer(buf)
+				if _, err := words.Lex(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
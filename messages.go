@@ -0,0 +1,40 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "fmt"
+
+// Lang selects the language (ex: "de", "pt-BR") of the Messages used
+// for every message generated by the package itself (see Expect,
+// Lexer, and such) so that tools built on scan can present diagnostics
+// in the locale of their users. When empty (or a message has no
+// translation) the original English is used.
+var Lang string
+
+// Messages is the catalog of translations keyed by language and then by
+// the original English message format (fmt.Sprintf verbs included, as
+// with gettext) to the translated format. The formats used are:
+//
+//	failed to scan      (DefaultErrorMessage)
+//	expected %v         (list of alternatives)
+//	%v or %v            (two alternatives)
+//	%v, or %v           (last of more than two)
+//	expected %q
+//	unexpected %q
+//	invalid statement
+//	panic in %v: %v     (hook name and value)
+//	more than %v tokens
+//	more than %v bytes of tokens
+var Messages = map[string]map[string]string{}
+
+// msg returns the formatted message translated (see Lang and Messages).
+func msg(form string, a ...any) string {
+	if t, has := Messages[Lang][form]; has {
+		form = t
+	}
+	if len(a) == 0 {
+		return form
+	}
+	return fmt.Sprintf(form, a...)
+}
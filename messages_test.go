@@ -0,0 +1,31 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleMessages() {
+	scan.Messages["de"] = map[string]string{
+		"expected %v":   "%v erwartet",
+		"%v or %v":      "%v oder %v",
+		"%v, or %v":     "%v oder %v",
+		"unexpected %q": "unerwartetes %q",
+	}
+	defer func() { scan.Lang = "" }()
+
+	for _, lang := range []string{"", "de", "fr"} {
+		scan.Lang = lang
+		s := new(scan.R)
+		s.Buffer("x $")
+		s.Expect("(", "[", "{")
+		lexer.Lex(s)
+		fmt.Println(s.Errors[0].(scan.Error).Msg, "|", s.Errors[1].(scan.Error).Msg)
+	}
+
+	// Output:
+	// expected '(', '[', or '{' | unexpected '$'
+	// '(', '[' oder '{' erwartet | unerwartetes '$'
+	// expected '(', '[', or '{' | unexpected '$'
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+// Middleware wraps the next Scan function (the one that advances one
+// rune) returning a new one so that cross-cutting concerns (statistics,
+// input filtering, rune substitution, and such) can be layered onto any
+// scanner without changing grammars or Scan itself (see Use).
+type Middleware func(next func(s *R) bool) func(s *R) bool
+
+// Use wraps Scan with the Middleware in order (the first is outermost
+// and called first). Calling with none removes all Middleware. Only Scan
+// is wrapped (not ScanByte, matching methods, or a Lexer).
+func (s *R) Use(mw ...Middleware) {
+	defer s.hook()
+	if len(mw) == 0 {
+		s.mw = nil
+		return
+	}
+	next := (*R).scan
+	if s.mw != nil {
+		next = s.mw
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		next = mw[i](next)
+	}
+	s.mw = next
+}
+
+// SkipANSI is Middleware that skips ANSI terminal escape sequences
+// (CSI sequences such as colors and cursor movement) so that grammars
+// can scan captured terminal output as if it were plain text.
+func SkipANSI(next func(s *R) bool) func(s *R) bool {
+	return func(s *R) bool {
+		for next(s) {
+			if s.R != '\x1b' {
+				return true
+			}
+			if !next(s) {
+				return false
+			}
+			if s.R != '[' {
+				return true // lone escape dropped
+			}
+			for next(s) && (s.R < 0x40 || s.R > 0x7e) {
+			}
+		}
+		return false
+	}
+}
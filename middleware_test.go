@@ -0,0 +1,41 @@
+package scan_test
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Use() {
+	var count int
+	counting := func(next func(s *scan.R) bool) func(s *scan.R) bool {
+		return func(s *scan.R) bool {
+			ok := next(s)
+			if ok {
+				count++
+			}
+			return ok
+		}
+	}
+	upper := func(next func(s *scan.R) bool) func(s *scan.R) bool {
+		return func(s *scan.R) bool {
+			ok := next(s)
+			s.R = unicode.ToUpper(s.R)
+			return ok
+		}
+	}
+
+	s := new(scan.R)
+	s.Buffer("\x1b[1;31merror\x1b[0m: ok")
+	s.Use(scan.SkipANSI, counting, upper)
+
+	var out []rune
+	for s.Scan() {
+		out = append(out, s.R)
+	}
+	fmt.Println(string(out), count)
+
+	// Output:
+	// ERROR: OK 20
+}
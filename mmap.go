@@ -0,0 +1,66 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "os"
+
+// OpenMapped is the same as Open for a single file except that the file
+// is memory-mapped (where supported) instead of read into the Go heap so
+// that very large inputs can be scanned (with Pointers, Goto, and such)
+// without copying them first. The mapping is private so changes to the
+// buffer (see NormalizeNewLines) are never written back to the file.
+// Call Unmap when done and do not use any slice of the buffer after.
+// Any previous mapping is unmapped first (as it is by Buffer and
+// everything that calls it). When Transcode is set (or mapping is not
+// supported) the file is simply read as with Open.
+func (s *R) OpenMapped(path string) error {
+	if s.Transcode {
+		return s.Open(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	b, err := mmap(f, int(info.Size()))
+	if err != nil {
+		return err
+	}
+	if err := s.Unmap(); err != nil {
+		munmap(b)
+		return err
+	}
+	s.Buffer(b)
+	s.Files = []File{{Name: path}}
+	s.mapped = b
+	return nil
+}
+
+// Unmap releases the memory mapping of OpenMapped (if any) and empties
+// the buffer (B) if it is still the mapped file since it can no longer
+// be used.
+func (s *R) Unmap() error {
+	if s.mapped == nil {
+		return nil
+	}
+	err := munmap(s.mapped)
+	mapped := s.mapped
+	s.mapped = nil // or Buffer unmaps it again
+	if sameArray(s.B, mapped) {
+		s.Buffer([]byte{})
+	}
+	return err
+}
+
+// sameArray returns true if both slices end at the same place in the
+// same underlying array (the buffer resliced from a mapping, for
+// example).
+func sameArray(a, b []byte) bool {
+	return cap(a) > 0 && cap(b) > 0 &&
+		&a[:cap(a)][cap(a)-1] == &b[:cap(b)][cap(b)-1]
+}
@@ -0,0 +1,21 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package scan
+
+import (
+	"io"
+	"os"
+)
+
+// mmap falls back to reading the whole file where mapping is not
+// supported.
+func mmap(f *os.File, size int) ([]byte, error) {
+	b := make([]byte, size)
+	_, err := io.ReadFull(f, b)
+	return b, err
+}
+
+func munmap(b []byte) error { return nil }
@@ -0,0 +1,48 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "sort"
+
+// NormalizeNewLines replaces every CRLF and lone CR in the buffer with
+// a single LF (in place) so that grammars need only handle LF while
+// keeping a table of the bytes removed so that Orig can convert any
+// offset (including BufByte, which like every other offset refers to
+// the normalized buffer) to one in the original input. Should be
+// called right after Buffer (or Open) before scanning since it resets
+// the position (but not the generation). Lines and columns are counted
+// from the normalized buffer and so lone CRs are counted as lines no
+// matter the NewLine setting.
+func (s *R) NormalizeNewLines() {
+	var removed []int
+	b := s.B
+	n, f := 0, 0
+	for i := 0; i < len(b); i++ {
+		for f < len(s.Files) && s.Files[f].Off <= i {
+			s.Files[f].Off = n
+			f++
+		}
+		c := b[i]
+		if c == '\r' {
+			c = '\n'
+			if i+1 < len(b) && b[i+1] == '\n' {
+				removed = append(removed, n)
+				i++
+			}
+		}
+		b[n] = c
+		n++
+	}
+	s.B = b[:n]
+	s.P, s.PP = 0, 0
+	s.ctr = counter{}
+	s.removed = removed
+}
+
+// Orig returns the byte offset (p) in the buffer as the offset in the
+// original input before NormalizeNewLines (and any bytes dropped from
+// a Stream, see Base).
+func (s R) Orig(p int) int {
+	return s.Base + p + sort.SearchInts(s.removed, p)
+}
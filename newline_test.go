@@ -0,0 +1,26 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_NormalizeNewLines() {
+	s := new(scan.R)
+	s.Buffer("one\r\ntwo\rthree\r\n👿\n")
+	s.NormalizeNewLines()
+	fmt.Printf("%q\n", s.B)
+
+	for s.Scan() {
+		if s.R == 't' || s.R == '👿' {
+			fmt.Println(s.Pos(), s.Orig(s.PP))
+		}
+	}
+
+	// Output:
+	// "one\ntwo\nthree\n👿\n"
+	// U+0074 't' 2,1-1 (5-5) 5
+	// U+0074 't' 3,1-1 (9-9) 9
+	// U+1F47F '👿' 4,1-1 (15-18) 16
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// File is a named region of the buffer beginning at the byte offset
+// (Off) and ending at the beginning of the next File (or end of buffer).
+// See Open.
+type File struct {
+	Name     string `json:"name"`
+	Off      int    `json:"off"`
+	Encoding string `json:"encoding,omitempty"` // see R.Transcode
+}
+
+// Open reads the files at the paths in order and buffers them as one
+// logical stream (see Buffer) recording each as a File so that every
+// Position (and therefore every Error) reports the file name along with
+// the line and columns within that file. When Transcode is set each file
+// is converted separately with its original encoding recorded in its
+// File (and that of the first in Encoding). This is useful for tools that
+// process sets of files (include-all directories and such) as if they
+// were one. Returns the first error encountered leaving the scanner
+// unchanged.
+func (s *R) Open(paths ...string) error {
+	return s.open(func(i int) ([]byte, error) {
+		return os.ReadFile(paths[i])
+	}, paths)
+}
+
+// OpenFS is the same as Open but reads the files from the file system
+// (fsys) so that content embedded with //go:embed (see embed.FS), test
+// fixtures (see testing/fstest), and such can be scanned directly.
+func (s *R) OpenFS(fsys fs.FS, paths ...string) error {
+	return s.open(func(i int) ([]byte, error) {
+		return fs.ReadFile(fsys, paths[i])
+	}, paths)
+}
+
+// BufferAll is the same as Buffer but for several sources (string,
+// []byte, or io.Reader) buffered in order as one logical stream with
+// each recorded as a File (see Open) so that every Position reports the
+// source it came from (a header prelude or a document body, for
+// example). Sources with a Name method (such as *os.File) are named by
+// it and the others by their place in the arguments (ex: "#2"). Returns
+// the first error encountered leaving the scanner unchanged.
+func (s *R) BufferAll(srcs ...any) error {
+	names := make([]string, len(srcs))
+	for i, src := range srcs {
+		names[i] = fmt.Sprintf("#%v", i+1)
+		if n, is := src.(interface{ Name() string }); is {
+			names[i] = n.Name()
+		}
+	}
+	return s.open(func(i int) ([]byte, error) {
+		switch v := srcs[i].(type) {
+		case string:
+			return []byte(v), nil
+		case []byte:
+			return v, nil
+		case io.Reader:
+			return io.ReadAll(v)
+		}
+		return nil, fmt.Errorf("unsupported source type: %T", srcs[i])
+	}, names)
+}
+
+// open buffers the content returned by read for each of the named
+// Files in order.
+func (s *R) open(read func(i int) ([]byte, error), names []string) error {
+	var buf []byte
+	files := make([]File, 0, len(names))
+	for i, name := range names {
+		b, err := read(i)
+		if err != nil {
+			return err
+		}
+		var enc string
+		if s.Transcode {
+			b, enc = transcode(b)
+		}
+		files = append(files, File{Name: name, Off: len(buf), Encoding: enc})
+		buf = append(buf, b...)
+	}
+	tc := s.Transcode
+	s.Transcode = false
+	s.Buffer(buf)
+	s.Transcode = tc
+	s.Files = files
+	if len(files) > 0 {
+		s.Encoding = files[0].Encoding
+	}
+	return nil
+}
+
+// fileAt returns the File containing the byte offset (p) of the buffer
+// or the zero File if there are none.
+func (s R) fileAt(p int) File {
+	var f File
+	for _, v := range s.Files {
+		if v.Off > p {
+			break
+		}
+		f = v
+	}
+	return f
+}
+
+// MaxURLBytes is the most that OpenURL will read before giving up.
+var MaxURLBytes int64 = 64 << 20
+
+// OpenURL fetches the http or https content at the URL (honoring the
+// context for cancellation and timeouts) and buffers it (see Buffer).
+// Returns an error (leaving the scanner unchanged) if the status is not
+// 2xx or if the content is larger than MaxURLBytes.
+func (s *R) OpenURL(ctx context.Context, rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme: %q", u.Scheme)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unable to fetch %v: %v", u, res.Status)
+	}
+	buf, err := io.ReadAll(io.LimitReader(res.Body, MaxURLBytes+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) > MaxURLBytes {
+		return fmt.Errorf("%v larger than MaxURLBytes (%v)", u, MaxURLBytes)
+	}
+	s.Buffer(buf)
+	return nil
+}
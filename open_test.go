@@ -0,0 +1,140 @@
+package scan_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing/fstest"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Open() {
+	s := new(scan.R)
+	if err := s.Open("testdata/one.txt", "testdata/two.txt"); err != nil {
+		fmt.Println(err)
+	}
+
+	for _, p := range s.Positions(3, 13, 20, 21, 29, 32) {
+		p.Print()
+	}
+
+	s.P = 29
+	s.Error("sample error")
+	fmt.Println(s.Errors[0])
+
+	fmt.Println(s.Open("testdata/missing.txt") != nil)
+
+	// Output:
+	// testdata/one.txt U+0072 'r' 1,3-3 (3-3)
+	// testdata/one.txt U+0069 'i' 2,2-2 (13-13)
+	// testdata/one.txt U+000A '\n' 3,0-0 (20-20)
+	// testdata/two.txt U+0073 's' 1,1-1 (21-21)
+	// testdata/two.txt U+0066 'f' 2,1-1 (29-29)
+	// testdata/two.txt U+0065 'e' 2,4-4 (32-32)
+	// sample error at testdata/two.txt U+0066 'f' 2,1-1 (29-29)
+	// true
+}
+
+func ExampleR_OpenFS() {
+	fsys := fstest.MapFS{
+		"head.txt": {Data: []byte("title: x\n")},
+		"body.txt": {Data: []byte("some\nbody\n")},
+	}
+
+	s := new(scan.R)
+	if err := s.OpenFS(fsys, "head.txt", "body.txt"); err != nil {
+		fmt.Println(err)
+	}
+	s.P = 15
+	s.Error("sample error")
+	fmt.Println(s.Errors[0])
+
+	fmt.Println(s.OpenFS(fsys, "missing.txt") != nil)
+
+	// Output:
+	// sample error at body.txt U+0062 'b' 2,1-1 (15-15)
+	// true
+}
+
+func ExampleR_BufferAll() {
+	s := new(scan.R)
+	err := s.BufferAll("%prelude\n", strings.NewReader("some\nbody\n"))
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, p := range s.Positions(2, 15) {
+		p.Print()
+	}
+
+	fmt.Println(s.BufferAll("ok", 42))
+
+	// Output:
+	// #1 U+0070 'p' 1,2-2 (2-2)
+	// #2 U+0062 'b' 2,1-1 (15-15)
+	// unsupported source type: int
+}
+
+func ExampleR_OpenMapped() {
+	s := new(scan.R)
+	if err := s.OpenMapped("testdata/two.txt"); err != nil {
+		fmt.Println(err)
+	}
+	defer s.Unmap()
+
+	s.ScanN(3)
+	s.Print()
+	s.Pos().Print()
+
+	fmt.Println(s.OpenMapped("testdata/missing.txt") != nil)
+
+	// a clone does not own the mapping
+	c := s.Clone()
+	fmt.Println(c.Unmap(), string(s.B[:6]))
+
+	// buffering something else releases it
+	s.Buffer("other")
+	fmt.Println(s.Unmap(), string(s.B))
+
+	// Output:
+	// 3 'c' "ond\r\nfile"
+	// testdata/two.txt U+0063 'c' 1,3-3 (3-3)
+	// true
+	// <nil> second
+	// <nil> other
+}
+
+func ExampleR_OpenURL() {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/spec.txt" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, "some remote thing")
+		}))
+	defer srv.Close()
+
+	s := new(scan.R)
+	fmt.Println(s.OpenURL(context.Background(), srv.URL+"/spec.txt"))
+	s.Scan()
+	s.Print()
+
+	fmt.Println(s.OpenURL(context.Background(), srv.URL+"/missing") != nil)
+	fmt.Println(s.OpenURL(context.Background(), "file:///etc/passwd"))
+	s.Print()
+
+	defer func(n int64) { scan.MaxURLBytes = n }(scan.MaxURLBytes)
+	scan.MaxURLBytes = 4
+	fmt.Println(s.OpenURL(context.Background(), srv.URL+"/spec.txt") != nil)
+
+	// Output:
+	// <nil>
+	// 1 's' "ome remote thing"
+	// true
+	// unsupported URL scheme: "file"
+	// 1 's' "ome remote thing"
+	// true
+}
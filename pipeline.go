@@ -0,0 +1,41 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+// Pipeline wires a Lexer and a token-level parser (Parse) together so
+// that the common lex-then-parse design needs no custom glue. Tokens
+// carry their Position so errors from both phases are reported against
+// the original buffer.
+type Pipeline struct {
+	Lexer Lexer
+	Parse func(t *T) bool
+}
+
+// Run lexes the buffer of the scanner (s) from its current position and
+// then calls Parse with a T of the resulting Tokens (if lexing
+// succeeded). Errors from both phases are combined into s.Errors (so
+// Report, Diagnostic, SARIF, and such work as usual) and the first of
+// them is returned. If Parse returns false without adding an error one
+// with the DefaultErrorMessage is added (and if it panics an Error with
+// the panic is added at the position it reached). The T (which traces to
+// the same Out) is returned so that the parser state can be inspected
+// afterward.
+func (p Pipeline) Run(s *R) (*T, error) {
+	toks, err := p.Lexer.Lex(s)
+	t := &T{Toks: toks, Out: s.Out}
+	if err != nil {
+		return t, err
+	}
+	var ok bool
+	if err := contain("Parse", func() { ok = p.Parse(t) }); err != nil {
+		t.Error("%v", err)
+	} else if !ok && len(t.Errors) == 0 {
+		t.Error()
+	}
+	s.Errors = append(s.Errors, t.Errors...)
+	if len(t.Errors) > 0 {
+		return t, t.Errors[0]
+	}
+	return t, nil
+}
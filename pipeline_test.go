@@ -0,0 +1,74 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExamplePipeline() {
+
+	// assign: Ident '=' (Ident / Number)
+	assign := func(t *scan.T) bool {
+		for !t.End() {
+			if !t.Peek(Ident) {
+				t.Error("expected identifier")
+				return false
+			}
+			t.Scan()
+			if !t.In(Op) {
+				t.Error("expected '='")
+				return false
+			}
+			t.Scan()
+			if !t.In(Ident, Number) {
+				t.Error("expected value")
+				return false
+			}
+			t.Scan()
+		}
+		return true
+	}
+
+	p := scan.Pipeline{Lexer: lexer, Parse: assign}
+
+	s := new(scan.R)
+	s.Buffer("x = 42\ny = x")
+	_, err := p.Run(s)
+	fmt.Println(err)
+
+	s.Buffer("x = 42\ny 1")
+	_, err = p.Run(s)
+	fmt.Println(err)
+
+	s.Buffer("x = $")
+	_, err = p.Run(s)
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// expected '=' at U+0031 '1' 2,3-3 (10-10)
+	// unexpected '$' at U+0024 '$' 1,5-5 (5-5)
+}
+
+func ExamplePipeline_panic() {
+	p := scan.Pipeline{
+		Lexer: lexer,
+		Parse: func(t *scan.T) bool {
+			for t.Scan() {
+				if t.T.Type == Op {
+					var m map[string]int
+					m[t.T.Text]++ // bug: nil map
+				}
+			}
+			return true
+		},
+	}
+	s := new(scan.R)
+	s.Buffer("x = 1")
+	_, err := p.Run(s)
+	fmt.Println(err)
+
+	// Output:
+	// panic in Parse: assignment to entry in nil map at U+0031 '1' 1,5-5 (5-5)
+}
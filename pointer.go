@@ -0,0 +1,133 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Pointer is a saved cursor of the scanner (see Ptr) to return to later
+// (see Goto). Unlike Mark it can be validated before it is used
+// including that it was saved from the same buffer (Gen). Hand-built
+// Pointers (with a Gen of 0) are only checked against the bounds of the
+// current buffer.
+type Pointer struct {
+	R   rune `json:"r"`   // last decoded rune
+	P   int  `json:"p"`   // index in buffer, points *after* R
+	PP  int  `json:"pp"`  // index of previous Scan, points *to* R
+	Gen int  `json:"gen"` // generation of buffer (see R.Gen)
+
+	Base int `json:"base,omitempty"` // of a stream when saved (see R.Base)
+}
+
+// Ptr returns a Pointer to the current cursor.
+func (s R) Ptr() Pointer { return Pointer{s.R, s.P, s.PP, s.Gen, s.Base} }
+
+// Goto restores the cursor from the Pointer after making sure it is
+// from the current buffer (Gen) and lies within it on rune boundaries
+// returning an error (leaving the scanner unchanged) otherwise. Pointers
+// into a stream are adjusted for the bytes dropped since they were saved
+// (see Stream) and ErrWindow is returned if they are no longer retained.
+// Hand-built Pointers should always be restored with Goto rather than
+// assigning the fields directly.
+func (s *R) Goto(m Pointer) error {
+	if m.Gen != 0 && m.Gen != s.Gen {
+		return fmt.Errorf("stale pointer from buffer generation %v (now %v)",
+			m.Gen, s.Gen)
+	}
+	if d := s.Base - m.Base; d != 0 {
+		m.P, m.PP = m.P-d, m.PP-d
+		if m.PP < 0 {
+			return ErrWindow
+		}
+	}
+	if err := s.check(m.PP); err != nil {
+		return err
+	}
+	if err := s.check(m.P); err != nil {
+		return err
+	}
+	if m.PP > m.P {
+		return fmt.Errorf("previous position (%v) after position (%v)",
+			m.PP, m.P)
+	}
+	s.R, s.P, s.PP = m.R, m.P, m.PP
+	return nil
+}
+
+// Jump moves the position (P) to the byte offset (p) updating the rune
+// (R) and previous position (PP) as if the rune just before it had been
+// scanned. Returns an error (leaving the scanner unchanged) if p is
+// not within the buffer or not on a rune boundary.
+func (s *R) Jump(p int) error {
+	if err := s.check(p); err != nil {
+		return err
+	}
+	s.goTo(p)
+	return nil
+}
+
+// Seek fulfills the io.Seeker interface by moving the position (P) to
+// the byte offset relative to the beginning of the input, the current
+// position, or the end of the buffer (see io.SeekStart, io.SeekCurrent,
+// io.SeekEnd) updating R and PP as with Jump. Unlike Jump, an offset
+// within a multibyte rune is moved back to the beginning of that rune
+// rather than rejected. Offsets are of the whole input (see R.Base) and
+// ErrWindow is returned for those no longer retained by a stream.
+// Returns the new offset or an error (leaving the scanner unchanged) if
+// it would be outside the buffer.
+func (s *R) Seek(offset int64, whence int) (int64, error) {
+	cur, p := int64(s.Base+s.P), int(offset)
+	switch whence {
+	case io.SeekStart:
+		p -= s.Base
+	case io.SeekCurrent:
+		p += s.P
+	case io.SeekEnd:
+		if s.src != nil {
+			return cur, errors.New("seek from end of stream")
+		}
+		p += len(s.B)
+	default:
+		return cur, fmt.Errorf("invalid whence: %v", whence)
+	}
+	if p < 0 && p+s.Base >= 0 {
+		return cur, ErrWindow
+	}
+	if p < 0 || p > len(s.B) {
+		return cur, fmt.Errorf("position %v outside buffer (%v)", p, len(s.B))
+	}
+	for p > 0 && p < len(s.B) && !utf8.RuneStart(s.B[p]) {
+		p--
+	}
+	s.goTo(p)
+	return int64(s.Base + p), nil
+}
+
+// check returns an error if the byte offset (p) is not within the buffer
+// (or just after it) or is not at the beginning of a rune.
+func (s R) check(p int) error {
+	if p < 0 || p > len(s.B) {
+		return fmt.Errorf("position %v outside buffer (%v)", p, len(s.B))
+	}
+	if p < len(s.B) && !utf8.RuneStart(s.B[p]) {
+		return fmt.Errorf("position %v not on rune boundary", p)
+	}
+	return nil
+}
+
+// PositionsOf returns the Position of each Pointer (in the same order)
+// resolving all of them in a single pass through the buffer (see
+// Positions). This matches the usual practice of saving Pointers while
+// scanning and only reporting their positions at the end.
+func (s R) PositionsOf(marks ...Pointer) []Position {
+	offs := make([]int, len(marks))
+	for i, m := range marks {
+		offs[i] = m.P - (s.Base - m.Base)
+	}
+	return s.Positions(offs...)
+}
@@ -0,0 +1,114 @@
+package scan_test
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Goto() {
+	s := new(scan.R)
+	s.B = []byte("a👿b")
+
+	s.Scan()
+	m := s.Ptr()
+	s.Scan()
+	s.Print()
+	fmt.Println(s.Goto(m))
+	s.Print()
+
+	fmt.Println(s.Goto(scan.Pointer{P: 3}))
+	fmt.Println(s.Goto(scan.Pointer{P: 9}))
+	fmt.Println(s.Goto(scan.Pointer{P: 1, PP: 5}))
+	s.Print()
+
+	// Output:
+	// 5 '👿' "b"
+	// <nil>
+	// 1 'a' "👿b"
+	// position 3 not on rune boundary
+	// position 9 outside buffer (6)
+	// previous position (5) after position (1)
+	// 1 'a' "👿b"
+}
+
+func ExampleR_Jump() {
+	s := new(scan.R)
+	s.B = []byte("a👿b")
+
+	fmt.Println(s.Jump(5))
+	s.Print()
+	fmt.Println(s.Jump(2))
+	fmt.Println(s.Jump(0))
+	s.Print()
+
+	// Output:
+	// <nil>
+	// 5 '👿' "b"
+	// position 2 not on rune boundary
+	// <nil>
+	// 0 '\x00' "a👿b"
+}
+
+func ExampleR_Seek() {
+	s := new(scan.R)
+	s.B = []byte("a👿b")
+
+	fmt.Println(s.Seek(3, io.SeekStart)) // within 👿
+	s.Print()
+	fmt.Println(s.Seek(4, io.SeekCurrent))
+	s.Print()
+	fmt.Println(s.Seek(-1, io.SeekEnd))
+	s.Print()
+	fmt.Println(s.Seek(2, io.SeekCurrent))
+	s.Print()
+
+	// Output:
+	// 1 <nil>
+	// 1 'a' "👿b"
+	// 5 <nil>
+	// 5 '👿' "b"
+	// 5 <nil>
+	// 5 '👿' "b"
+	// 5 position 7 outside buffer (6)
+	// 5 '👿' "b"
+}
+
+func ExampleR_Goto_stale() {
+	s := new(scan.R)
+	s.Buffer("some thing")
+	s.Scan()
+	m := s.Ptr()
+
+	s.Buffer("other thing")
+	fmt.Println(s.Goto(m))
+
+	m.Gen = 0 // hand-built
+	fmt.Println(s.Goto(m))
+
+	// Output:
+	// stale pointer from buffer generation 1 (now 2)
+	// <nil>
+}
+
+func ExampleR_PositionsOf() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand another")
+
+	var marks []scan.Pointer
+	for s.Scan() {
+		if s.R == 'n' {
+			marks = append(marks, s.Ptr())
+		}
+	}
+	for _, p := range s.PositionsOf(marks...) {
+		p.Print()
+	}
+
+	// Output:
+	// U+006E 'n' 1,2-2 (2-2)
+	// U+006E 'n' 1,7-7 (7-7)
+	// U+006E 'n' 2,2-2 (11-11)
+	// U+006E 'n' 2,6-6 (15-15)
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "sync"
+
+// Pool (built on sync.Pool) hands out cleared scanners for servers
+// scanning many small inputs concurrently. The Config function (if set)
+// is called for every scanner handed out so that all of them share the
+// same configuration (NewLine, Template, Track, and such). Nothing set
+// by a previous borrower (watches, breakpoints, Middleware, Trace, Out,
+// and such) is ever handed out again. The zero value is ready to use.
+type Pool struct {
+	Config func(s *R)
+	pool   sync.Pool
+}
+
+// Get returns a scanner from the Pool (or a new one) configured (see
+// Config) with the buffer (see Buffer).
+func (p *Pool) Get(buf any) *R {
+	s, _ := p.pool.Get().(*R)
+	if s == nil {
+		s = new(R)
+	}
+	if p.Config != nil {
+		p.Config(s)
+	}
+	s.Buffer(buf)
+	return s
+}
+
+// Put returns the scanner to the Pool clearing all of its state (but
+// keeping the allocated capacity of Errors and the generation of its
+// buffer, see Gen) so that nothing is retained. The scanner must not be
+// used after.
+func (p *Pool) Put(s *R) {
+	s.Unmap()
+	*s = R{Errors: s.Errors[:0], Gen: s.Gen}
+	p.pool.Put(s)
+}
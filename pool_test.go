@@ -0,0 +1,58 @@
+package scan_test
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExamplePool() {
+	pool := scan.Pool{Config: func(s *scan.R) {
+		s.NewLine = scan.UnicodeNewLine
+		s.Track = true
+	}}
+
+	inputs := []string{"one\u2028two", "three\u2028four"}
+	lines := make([]int, len(inputs))
+
+	var wg sync.WaitGroup
+	for i, in := range inputs {
+		wg.Add(1)
+		go func(i int, in string) {
+			defer wg.Done()
+			s := pool.Get(in)
+			defer pool.Put(s)
+			for s.Scan() {
+			}
+			lines[i] = s.Pos().Line
+		}(i, in)
+	}
+	wg.Wait()
+	fmt.Println(lines)
+
+	// Output:
+	// [2 2]
+}
+
+func ExamplePool_reuse() {
+	var pool scan.Pool
+
+	var todos int
+	s := pool.Get("TODO: one")
+	s.Watch(regexp.MustCompile(`TODO:`), func(scan.Span) { todos++ })
+	for s.Scan() {
+	}
+	s.Error("sample error")
+	pool.Put(s)
+
+	// nothing set by the previous borrower is handed out again
+	s = pool.Get("TODO: two")
+	for s.Scan() {
+	}
+	fmt.Println(todos, len(s.Errors))
+
+	// Output:
+	// 1 0
+}
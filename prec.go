@@ -0,0 +1,139 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "fmt"
+
+// Op is the precedence (higher binds tighter) and associativity of
+// a binary operator (see Prec).
+type Op struct {
+	Prec  int
+	Right bool // right-associative (ex: exponentiation, assignment)
+}
+
+// Prec parses expressions of binary operators from a table (Ops keyed
+// by Token type) by precedence climbing so that grammars need not
+// encode each level of precedence as another layered rule (see the
+// calc example for the layered way). Operand parses everything between
+// the operators (numbers, unary operators, parenthesized expressions
+// calling Parse again, and such) and must add its own Errors. Combine
+// builds the node for each operator and its operands (a Binary when
+// Combine is nil).
+type Prec struct {
+	Ops     map[int]Op
+	Operand func(t *T) (any, bool)
+	Combine func(op Token, l, r any) any
+}
+
+// Binary is the default node built by Prec for each operator.
+type Binary struct {
+	Op   Token
+	L, R any
+}
+
+// String implements fmt.Stringer fully parenthesized (ex: "(1 + (2 * 3))").
+func (b Binary) String() string { return fmt.Sprintf("(%v %v %v)", b.L, b.Op.Text, b.R) }
+
+// Parse returns the node for the longest expression at the current
+// position of the token scanner (t) or false (with the position
+// unchanged) if an operand fails.
+func (p Prec) Parse(t *T) (any, bool) {
+	tok, pos, prev := t.Mark()
+	v, ok := p.climb(t, 0)
+	if !ok {
+		t.Back(tok, pos, prev)
+	}
+	return v, ok
+}
+
+func (p Prec) climb(t *T, min int) (any, bool) {
+	l, ok := p.Operand(t)
+	if !ok {
+		return nil, false
+	}
+	for !t.End() {
+		op, is := p.Ops[t.Toks[t.P].Type]
+		if !is || op.Prec < min {
+			break
+		}
+		t.Scan()
+		tok := t.T
+		next := op.Prec + 1
+		if op.Right {
+			next = op.Prec
+		}
+		r, ok := p.climb(t, next)
+		if !ok {
+			return nil, false
+		}
+		if p.Combine != nil {
+			l = p.Combine(tok, l, r)
+			continue
+		}
+		l = binary(tok, l, r)
+	}
+	return l, true
+}
+
+// Chain parses the flat repetition operand (op operand)* where each op
+// is a Token of any of the types returning the operands and operators
+// in order (see FoldLeft and FoldRight) or false (with the position
+// unchanged) if an operand fails.
+func Chain(t *T, operand func(t *T) (any, bool), ops ...int) ([]any, []Token, bool) {
+	tok, pos, prev := t.Mark()
+	v, ok := operand(t)
+	if !ok {
+		t.Back(tok, pos, prev)
+		return nil, nil, false
+	}
+	nodes := []any{v}
+	var toks []Token
+	for t.In(ops...) {
+		t.Scan()
+		toks = append(toks, t.T)
+		v, ok := operand(t)
+		if !ok {
+			t.Back(tok, pos, prev)
+			return nil, nil, false
+		}
+		nodes = append(nodes, v)
+	}
+	return nodes, toks, true
+}
+
+// FoldLeft combines the flat chain of nodes and the operators between
+// them (one fewer, see Chain) into a left-associative tree such that
+// a-b-c is ((a-b)-c). Combine builds each node (a Binary when nil).
+// Panics if the number of ops is not one less than nodes.
+func FoldLeft(nodes []any, ops []Token, combine func(op Token, l, r any) any) any {
+	if len(ops) != len(nodes)-1 {
+		panic("fold: need one less op than nodes")
+	}
+	if combine == nil {
+		combine = binary
+	}
+	l := nodes[0]
+	for i, op := range ops {
+		l = combine(op, l, nodes[i+1])
+	}
+	return l
+}
+
+// FoldRight is the same as FoldLeft but right-associative such that
+// a^b^c is (a^(b^c)).
+func FoldRight(nodes []any, ops []Token, combine func(op Token, l, r any) any) any {
+	if len(ops) != len(nodes)-1 {
+		panic("fold: need one less op than nodes")
+	}
+	if combine == nil {
+		combine = binary
+	}
+	r := nodes[len(nodes)-1]
+	for i := len(ops) - 1; i >= 0; i-- {
+		r = combine(ops[i], nodes[i], r)
+	}
+	return r
+}
+
+func binary(op Token, l, r any) any { return Binary{op, l, r} }
@@ -0,0 +1,149 @@
+package scan_test
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExamplePrec() {
+	lexer, names := scan.MustParseLexer(`
+-space \s+
+Num    \d+
+Name   \p{L}+
+Set    =
+Or     \|\|
+Add    \+
+Sub    -
+Mul    \*
+Pow    \^
+`)
+	types := map[string]int{}
+	for i, n := range names {
+		types[n] = i
+	}
+
+	var p scan.Prec
+	p.Ops = map[int]scan.Op{
+		types["Set"]: {1, true},
+		types["Or"]:  {2, false},
+		types["Add"]: {3, false},
+		types["Sub"]: {3, false},
+		types["Mul"]: {4, false},
+		types["Pow"]: {5, true},
+	}
+	p.Operand = func(t *scan.T) (any, bool) {
+		if !t.In(types["Num"], types["Name"]) {
+			t.Error("expected operand")
+			return nil, false
+		}
+		t.Scan()
+		return t.T.Text, true
+	}
+
+	for _, in := range []string{
+		"1 + 2 * 3 - 4",
+		"a = b = 2 ^ 3 ^ 2",
+		"x || 1 - 2 - 3 * y",
+		"1 + * 2",
+	} {
+		s := new(scan.R)
+		s.Buffer(in)
+		toks, _ := lexer.Lex(s)
+		t := &scan.T{Toks: toks}
+		v, ok := p.Parse(t)
+		fmt.Println(v, ok, t.P, t.Errors)
+	}
+
+	// evaluate instead of building a tree
+	p.Combine = func(op scan.Token, l, r any) any {
+		a, b := l.(float64), r.(float64)
+		switch names[op.Type] {
+		case "Add":
+			return a + b
+		case "Sub":
+			return a - b
+		case "Mul":
+			return a * b
+		case "Pow":
+			return math.Pow(a, b)
+		}
+		return math.NaN()
+	}
+	p.Operand = func(t *scan.T) (any, bool) {
+		t.Scan()
+		v, err := strconv.ParseFloat(t.T.Text, 64)
+		return v, err == nil
+	}
+	s := new(scan.R)
+	s.Buffer("2 ^ 3 ^ 2 - 10 - 1")
+	toks, _ := lexer.Lex(s)
+	fmt.Println(p.Parse(&scan.T{Toks: toks}))
+
+	// Output:
+	// ((1 + (2 * 3)) - 4) true 7 []
+	// (a = (b = (2 ^ (3 ^ 2)))) true 9 []
+	// (x || ((1 - 2) - (3 * y))) true 9 []
+	// <nil> false 0 [expected operand at U+002A '*' 1,5-5 (5-5)]
+	// 501 true
+}
+
+func ExampleFoldLeft() {
+	lexer, _ := scan.MustParseLexer(`
+-space \s+
+Num    \d+
+Op     [-^]
+`)
+	s := new(scan.R)
+	s.Buffer("8 - 4 - 2")
+	toks, _ := lexer.Lex(s)
+
+	num := func(t *scan.T) (any, bool) {
+		if !t.In(1) {
+			return nil, false
+		}
+		t.Scan()
+		return t.T.Text, true
+	}
+	nodes, ops, ok := scan.Chain(&scan.T{Toks: toks}, num, 2)
+	fmt.Println(nodes, len(ops), ok)
+	fmt.Println(scan.FoldLeft(nodes, ops, nil))
+	fmt.Println(scan.FoldRight(nodes, ops, nil))
+
+	// Output:
+	// [8 4 2] 2 true
+	// ((8 - 4) - 2)
+	// (8 - (4 - 2))
+}
+
+func ExampleChain() {
+	lexer, _ := scan.MustParseLexer(`
+-space \s+
+Num    \d+
+Op     [-^]
+`)
+	s := new(scan.R)
+	s.Buffer("- 4 - 2")
+	toks, _ := lexer.Lex(s)
+
+	// scans a Token before knowing if it is a Num
+	num := func(t *scan.T) (any, bool) {
+		if !t.Scan() || t.T.Type != 1 {
+			return nil, false
+		}
+		return t.T.Text, true
+	}
+	t := &scan.T{Toks: toks}
+	_, _, ok := scan.Chain(t, num, 2)
+	fmt.Println(ok, t.P)
+
+	t.Scan()
+	nodes, ops, ok := scan.Chain(t, num, 2)
+	fmt.Println(nodes, len(ops), ok, t.P)
+
+	// Output:
+	// false 0
+	// [4 2] 1 true 4
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// REPLHelp is printed by the REPL for the :help command.
+const REPLHelp = `:re EXPR   set the regular expression to match
+:trace     toggle tracing of every rune scanned by the match
+:help      print this help
+:quit      exit (as does end of input)
+Any other line is input to match (with \n, \t, and \\ escapes) against
+the expression at its beginning.`
+
+// REPL reads lines from r and writes results to w so that expressions
+// can be learned and tested interactively. Lines beginning with a colon
+// are commands (see REPLHelp) and every other line is sample input that
+// is matched against the current expression printing the consumed text,
+// the rest, and the submatches with their positions. When trace is
+// toggled on each rune of the match is logged as it is scanned (see
+// R.Trace). Returns any error reading r.
+func REPL(r io.Reader, w io.Writer) error {
+	var re *regexp.Regexp
+	s := &R{Out: w}
+	in := bufio.NewScanner(r)
+	prompt := func() { fmt.Fprint(w, "> ") }
+	for prompt(); in.Scan(); prompt() {
+		line := in.Text()
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch cmd {
+
+		case ":re":
+			x, err := regexp.Compile(arg)
+			if err != nil {
+				fmt.Fprintln(w, "error:", err)
+				continue
+			}
+			re = x
+
+		case ":trace":
+			s.Trace = 1 - s.Trace
+			fmt.Fprintln(w, "trace", s.Trace == 1)
+
+		case ":help":
+			fmt.Fprintln(w, REPLHelp)
+
+		case ":quit":
+			return nil
+
+		default:
+			if re == nil {
+				fmt.Fprintln(w, "error: no expression (see :re)")
+				continue
+			}
+			s.Buffer(strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\\`, `\`).Replace(line))
+			loc := re.FindSubmatchIndex(s.B)
+			if loc == nil || loc[0] != 0 {
+				fmt.Fprintln(w, "no match")
+				continue
+			}
+			for s.P < loc[1] && s.Scan() {
+			}
+			fmt.Fprintf(w, "match %q rest %q\n", s.B[:loc[1]], s.B[loc[1]:])
+			for i := 2; i < len(loc); i += 2 {
+				if loc[i] < 0 {
+					fmt.Fprintf(w, "%v: none\n", i/2)
+					continue
+				}
+				fmt.Fprintf(w, "%v: %q at %v\n", i/2, s.B[loc[i]:loc[i+1]],
+					s.Positions(loc[i] + 1)[0])
+			}
+		}
+	}
+	return in.Err()
+}
@@ -0,0 +1,34 @@
+package scan_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleREPL() {
+	scan.REPL(strings.NewReader(`abc
+:re (\p{L}+)(\d)?
+ab1 rest
+:trace
+xy
+:re (
+42
+`), os.Stdout)
+
+	// Output:
+	// > error: no expression (see :re)
+	// > > match "ab1" rest " rest"
+	// 1: "ab" at U+0061 'a' 1,1-1 (1-1)
+	// 2: "1" at U+0031 '1' 1,3-3 (3-3)
+	// > trace true
+	// > 1 'x' "y"
+	// 2 'y' ""
+	// match "xy" rest ""
+	// 1: "xy" at U+0078 'x' 1,1-1 (1-1)
+	// 2: none
+	// > error: error parsing regexp: missing closing ): `(`
+	// > no match
+	// >
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sampler generates random strings that a regular expression (or a
+// Lexer) accepts for fuzzing downstream systems and for checking that
+// an expression means what its author thinks. Zero-width assertions
+// (^, $, \b, and such) are ignored so samples containing them may not
+// match. The zero value uses a random seed and a MaxRepeat of 3.
+type Sampler struct {
+	Rand      *rand.Rand // source of randomness (set with a seed to repeat)
+	MaxRepeat int        // most extra repetitions for *, +, and {n,}
+}
+
+// Sample returns a random string matching the regular expression.
+// Returns an error only if the expression cannot match anything.
+func (g Sampler) Sample(re *regexp.Regexp) (string, error) {
+	x, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	if g.Rand == nil {
+		g.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	if g.MaxRepeat <= 0 {
+		g.MaxRepeat = 3
+	}
+	var buf strings.Builder
+	if err := g.gen(&buf, x.Simplify()); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SampleTokens returns a random string of n tokens (each from a random
+// non-Skip rule of the Lexer) separated by samples of the first Skip
+// rule (if any) along with the Token types in order. Note that
+// adjacent tokens may lex differently than generated if there is no
+// Skip rule to separate them.
+func (g Sampler) SampleTokens(l Lexer, n int) (string, []int, error) {
+	if g.Rand == nil {
+		g.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	var sep *regexp.Regexp
+	var rules []Rule
+	for _, r := range l.Rules {
+		switch {
+		case r.Skip && sep == nil:
+			sep = r.Re
+		case !r.Skip:
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == 0 {
+		return "", nil, fmt.Errorf("no rules to sample")
+	}
+	var buf strings.Builder
+	var types []int
+	for i := 0; i < n; i++ {
+		if i > 0 && sep != nil {
+			for {
+				s, err := g.Sample(sep)
+				if err != nil {
+					return "", nil, err
+				}
+				if len(s) > 0 {
+					buf.WriteString(s)
+					break
+				}
+			}
+		}
+		r := rules[g.Rand.IntN(len(rules))]
+		s, err := g.Sample(r.Re)
+		if err != nil {
+			return "", nil, err
+		}
+		buf.WriteString(s)
+		types = append(types, r.Type)
+	}
+	return buf.String(), types, nil
+}
+
+func (g Sampler) gen(buf *strings.Builder, x *syntax.Regexp) error {
+	switch x.Op {
+
+	case syntax.OpNoMatch:
+		return fmt.Errorf("expression cannot match: %v", x)
+
+	case syntax.OpLiteral:
+		for _, r := range x.Rune {
+			if x.Flags&syntax.FoldCase != 0 && g.Rand.IntN(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			buf.WriteRune(r)
+		}
+
+	case syntax.OpCharClass:
+		if len(x.Rune) == 0 {
+			return fmt.Errorf("expression cannot match: %v", x)
+		}
+		// most classes in practice are small so prefer ASCII when allowed
+		for range 10 {
+			i := g.Rand.IntN(len(x.Rune)/2) * 2
+			lo, hi := x.Rune[i], x.Rune[i+1]
+			if lo < utf8.RuneSelf && hi >= utf8.RuneSelf {
+				hi = utf8.RuneSelf - 1
+			}
+			r := lo + g.Rand.Int32N(hi-lo+1)
+			if utf8.ValidRune(r) {
+				buf.WriteRune(r)
+				return nil
+			}
+		}
+		buf.WriteRune(x.Rune[0])
+
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		buf.WriteRune(' ' + g.Rand.Int32N('~'-' '+1))
+
+	case syntax.OpCapture:
+		return g.gen(buf, x.Sub[0])
+
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := x.Min, x.Max
+		switch x.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max < 0 {
+			max = min + g.MaxRepeat
+		}
+		for n := min + g.Rand.IntN(max-min+1); n > 0; n-- {
+			if err := g.gen(buf, x.Sub[0]); err != nil {
+				return err
+			}
+		}
+
+	case syntax.OpConcat:
+		for _, sub := range x.Sub {
+			if err := g.gen(buf, sub); err != nil {
+				return err
+			}
+		}
+
+	case syntax.OpAlternate:
+		return g.gen(buf, x.Sub[g.Rand.IntN(len(x.Sub))])
+	}
+
+	// empty match and zero-width assertions produce nothing
+	return nil
+}
@@ -0,0 +1,49 @@
+package scan_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleSampler() {
+	g := scan.Sampler{Rand: rand.New(rand.NewPCG(1, 2))}
+	re := regexp.MustCompile(`(?i)[a-z_]\w{0,4}|0x[0-9A-F]+|"[^"\n]*"`)
+	whole := regexp.MustCompile(`^(?:` + re.String() + `)$`)
+	for range 5 {
+		s, _ := g.Sample(re)
+		fmt.Printf("%q %v\n", s, whole.MatchString(s))
+	}
+
+	_, err := g.Sample(regexp.MustCompile(`[^\x00-\x{10FFFF}]`))
+	fmt.Println(err)
+
+	// Output:
+	// "\"\"" true
+	// "M" true
+	// "R" true
+	// "\"f\f\"" true
+	// "ſ" true
+	// expression cannot match: [^\x00-\x{10FFFF}]
+}
+
+func ExampleSampler_SampleTokens() {
+	g := scan.Sampler{Rand: rand.New(rand.NewPCG(1, 2))}
+	in, types, _ := g.SampleTokens(lexer, 6)
+	fmt.Printf("%q %v\n", in, types)
+
+	s := new(scan.R)
+	s.Buffer(in)
+	toks, err := lexer.Lex(s)
+	var got []int
+	for _, t := range toks {
+		got = append(got, t.Type)
+	}
+	fmt.Println(got, err)
+
+	// Output:
+	// "*\t\f\tꙬⳮ\r\n\r\t=\r/ \t 𑌉〼𐁜𖵥\r\n+" [3 1 3 3 1 3]
+	// [3 1 3 3 1 3] <nil>
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "encoding/json"
+
+// SARIFVersion is the version of the SARIF schema produced by SARIF.
+const SARIFVersion = `2.1.0`
+
+// SARIFSchema is the URI of the SARIF JSON schema.
+const SARIFSchema = `https://json.schemastore.org/sarif-2.1.0.json`
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver struct {
+		Name string `json:"name"`
+	} `json:"driver"`
+}
+
+type sarifResult struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn"`
+			ByteOffset  int `json:"byteOffset"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// SARIF returns the Errors as a SARIF (Static Analysis Results
+// Interchange Format) log with a single run of the named tool and one
+// error result for each located in the artifact at uri (or the File it
+// is in when the buffer has several, see Open, with the byteOffset
+// within that File). The byteOffset is that of the original input (see
+// Orig) so that it matches the artifact. This allows lint tools built on scan to report
+// directly to GitHub code scanning and other SARIF consumers. Columns
+// are in runes (unicodeCodePoints) and positions are populated as with
+// Diagnostic.
+func (s R) SARIF(tool, uri string) ([]byte, error) {
+	run := sarifRun{ColumnKind: `unicodeCodePoints`}
+	run.Tool.Driver.Name = tool
+	run.Results = []sarifResult{}
+	for _, e := range s.Errs() {
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = uri
+		if e.Pos.File != "" {
+			loc.PhysicalLocation.ArtifactLocation.URI = e.Pos.File
+		}
+		reg := &loc.PhysicalLocation.Region
+		reg.StartLine, reg.StartColumn = e.Pos.Line, e.Pos.LRune
+		if reg.StartLine < 1 {
+			reg.StartLine = 1
+		}
+		if reg.StartColumn < 1 {
+			reg.StartColumn = 1
+		}
+		p := max(e.Pos.BufByte-len(string(e.Pos.Rune))-s.Base, 0)
+		reg.ByteOffset = s.Orig(p)
+		if len(s.Files) > 0 {
+			reg.ByteOffset -= s.Orig(s.fileAt(p).Off)
+		}
+		run.Results = append(run.Results, sarifResult{
+			Level:     `error`,
+			Message:   sarifMessage{e.Msg},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	return json.MarshalIndent(sarifLog{
+		Version: SARIFVersion,
+		Schema:  SARIFSchema,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
@@ -0,0 +1,108 @@
+package scan_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_SARIF() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand another")
+	s.P = 12
+	s.Error("sample error")
+
+	buf, err := s.SARIF("mylint", "file:///tmp/sample.txt")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(string(buf))
+
+	// Output:
+	// {
+	//   "version": "2.1.0",
+	//   "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+	//   "runs": [
+	//     {
+	//       "tool": {
+	//         "driver": {
+	//           "name": "mylint"
+	//         }
+	//       },
+	//       "columnKind": "unicodeCodePoints",
+	//       "results": [
+	//         {
+	//           "level": "error",
+	//           "message": {
+	//             "text": "sample error"
+	//           },
+	//           "locations": [
+	//             {
+	//               "physicalLocation": {
+	//                 "artifactLocation": {
+	//                   "uri": "file:///tmp/sample.txt"
+	//                 },
+	//                 "region": {
+	//                   "startLine": 2,
+	//                   "startColumn": 3,
+	//                   "byteOffset": 11
+	//                 }
+	//               }
+	//             }
+	//           ]
+	//         }
+	//       ]
+	//     }
+	//   ]
+	// }
+}
+
+func ExampleR_SARIF_files() {
+	s := new(scan.R)
+	if err := s.Open("testdata/one.txt", "testdata/two.txt"); err != nil {
+		fmt.Println(err)
+	}
+	s.P = 29
+	s.Error("sample error")
+
+	buf, _ := s.SARIF("mylint", "unused")
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				Locations []struct {
+					PhysicalLocation any
+				}
+			}
+		}
+	}
+	json.Unmarshal(buf, &log)
+	fmt.Println(log.Runs[0].Results[0].Locations[0].PhysicalLocation)
+
+	// Output:
+	// map[artifactLocation:map[uri:testdata/two.txt] region:map[byteOffset:8 startColumn:1 startLine:2]]
+}
+
+func ExampleR_SARIF_normalized() {
+	s := new(scan.R)
+	s.Buffer("a\r\n\r\nb")
+	s.NormalizeNewLines()
+	s.P = 4
+	s.Error("sample error")
+
+	buf, _ := s.SARIF("mylint", "x.txt")
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				Locations []struct {
+					PhysicalLocation any
+				}
+			}
+		}
+	}
+	json.Unmarshal(buf, &log)
+	fmt.Println(log.Runs[0].Results[0].Locations[0].PhysicalLocation)
+
+	// Output:
+	// map[artifactLocation:map[uri:x.txt] region:map[byteOffset:5 startColumn:1 startLine:3]]
+}
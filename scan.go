@@ -10,11 +10,16 @@ package scan
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"regexp"
+	"sort"
+	"strings"
 	"text/template"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -23,6 +28,25 @@ import (
 // does not give access to the equivalent R.Trace property.
 var Trace int
 
+// TraceColor is a Trace flag (bit) that adds ANSI terminal colors to
+// trace output (ex: s.Trace = 1 | scan.TraceColor) highlighting what
+// was scanned in green and errors (which are then traced as well) in
+// red. A T also shows its rule Stack (see T.Enter).
+const TraceColor = 2
+
+// NewLine is the default set of newline sequences used when counting
+// lines for a Position if R.NewLine is not set. The longest sequence
+// matching always wins so CRLF counts as exactly one line (with no
+// columns) whenever "\r\n" is included, no matter the order. Leave it
+// out (ex: []string{"\r", "\n"}) to count CR and LF as separate lines.
+var NewLine = []string{"\r\n", "\n"}
+
+// UnicodeNewLine adds the Unicode line separator (U+2028), paragraph
+// separator (U+2029), and next line (NEL, U+0085) to the usual newline
+// sequences for input produced by JavaScript and mainframe systems.
+// Assign it to R.NewLine (or scan.NewLine) to count them as lines.
+var UnicodeNewLine = []string{"\r\n", "\n", "\u0085", "\u2028", "\u2029"}
+
 // ViewLen sets the number of bytes to view before eliding the rest.
 var ViewLen = 20
 
@@ -44,7 +68,30 @@ type R struct {
 	Trace    int                // activate trace log (>0)
 	Errors   []error            // stack of errors in order
 	Template *template.Template // for Report()
-	NewLine  []string           // []string{"\r\n","\n"} by default
+	NewLine  []string           // scan.NewLine by default
+	Track    bool               // count lines during Scan (see Pos)
+	Out      io.Writer          // for Report and Log (log if nil)
+	JSON     bool               // Report a Diagnostic as JSON
+	Files    []File             // regions of B from different files
+	Break    func(s *R)         // called at breakpoints (see BreakAt)
+	Gen      int                // generation of B, Buffer increments
+	Base     int                // bytes of a stream dropped before B (see Stream)
+
+	Transcode bool   // convert UTF-16 and Latin-1 input to UTF-8 in Buffer
+	Encoding  string // original encoding detected when Transcode is set
+
+	ctr     counter      // incremental line counts when Track is set
+	breakAt map[int]bool // byte offsets to break at after Scan
+	breakOn map[int]bool // Token types to break on (see Lexer)
+	watches []watch      // expressions checked during Scan (see Watch)
+	src     io.Reader    // of a stream (see Stream)
+	window  int          // bytes kept behind P when streaming
+	origin  counter      // counts up to B[0] when streaming
+	removed []int        // offsets of CRs removed (see NormalizeNewLines)
+	mapped  []byte       // memory-mapped file (see OpenMapped)
+
+	mw     func(s *R) bool // Scan wrapped by Middleware (see Use)
+	hooked bool            // Scan has more to do than advance (see hook)
 }
 
 func (s *R) Bytes() []byte       { return s.B }
@@ -58,7 +105,11 @@ func (s *R) SetPrev(p int)       { s.PP = p }
 
 // Buffer sets the internal bytes buffer and initializes all internal
 // pointers and state. This is useful when testing in order to buffer
-// strings as well as content from any io.Reader.
+// strings as well as content from any io.Reader. If Transcode is set
+// the input is first converted to UTF-8 (see Encoding). The generation (Gen)
+// is incremented so that any Pointer to the previous buffer is rejected
+// (see Goto). Any memory mapping of the previous buffer is released (see
+// OpenMapped).
 func (s *R) Buffer(b any) {
 	switch v := b.(type) {
 	case string:
@@ -73,8 +124,39 @@ func (s *R) Buffer(b any) {
 		}
 		s.B = b
 	}
+	if s.mapped != nil {
+		munmap(s.mapped)
+		s.mapped = nil
+	}
+	s.Encoding = ""
+	if s.Transcode {
+		s.B, s.Encoding = transcode(s.B)
+	}
 	s.P = 0
 	s.PP = 0
+	s.Files = nil
+	s.ctr = counter{}
+	s.Gen++
+	s.src = nil
+	s.Base = 0
+	s.origin = counter{}
+	s.removed = nil
+	s.hook()
+}
+
+// Reset returns the scanner to the beginning of the buffer (which is
+// kept along with its Files) clearing the last rune (R) and the Errors
+// (keeping their allocated capacity) so that the same input can be
+// scanned again (under another grammar, for example) or, after Buffer,
+// a single scanner can be reused for many inputs. Any slice of Errors
+// retained from before the Reset may be overwritten. A stream is only
+// returned to the beginning of what it still retains (see Stream).
+func (s *R) Reset() {
+	s.P = 0
+	s.PP = 0
+	s.R = 0
+	s.ctr = counter{}
+	s.ClearErrors()
 }
 
 const DefaultTemplate = `
@@ -101,18 +183,22 @@ func init() {
 
 // Position contains the human-friendly information about the position
 // within a give text file. Note that all values begin with 1 and not
-// 0.
+// 0. When the buffer contains multiple Files the File name is set and
+// Line and the line columns are those within that file while BufByte
+// and BufRune are always relative to the entire buffer.
 type Position struct {
-	Rune    rune // rune at this location
-	BufByte int  // byte offset in file
-	BufRune int  // rune offset in file
-	Line    int  // line offset
-	LByte   int  // line column byte offset
-	LRune   int  // line column rune offset
+	File    string `json:"file,omitempty"` // name of File, if any
+	Rune    rune   `json:"rune"`           // rune at this location
+	BufByte int    `json:"byte"`           // byte offset in file
+	BufRune int    `json:"brune"`          // rune offset in file
+	Line    int    `json:"line"`           // line offset
+	LByte   int    `json:"lbyte"`          // line column byte offset
+	LRune   int    `json:"lrune"`          // line column rune offset
 }
 
 // String fulfills the fmt.Stringer interface by printing
-// the Position in a human-friendly way:
+// the Position in a human-friendly way (prefixed by the File name and
+// a space when set):
 //
 //   U+1F47F '👿' 1,3-5 (3-5)
 //                | | |  | |
@@ -126,6 +212,9 @@ func (p Position) String() string {
 		p.Line, p.LRune, p.LByte,
 		p.BufRune, p.BufByte,
 	)
+	if p.File != "" {
+		s = p.File + " " + s
+	}
 	return s
 }
 
@@ -136,9 +225,149 @@ func (p Position) Print() { fmt.Println(p.String()) }
 func (p Position) Log() { log.Println(p.String()) }
 
 // Pos returns a human-friendly Position for the current location.
-// When multiple positions are needed use Positions instead.
+// When multiple positions are needed use Positions instead. If Track
+// is set and the position (P) has only moved forward since the last
+// Scan the Position is counted from there without walking the buffer
+// again.
+func (s R) Pos() Position {
+	if s.Track && s.ctr.line > 0 && s.ctr.at <= s.P && s.P > 0 {
+		c := s.ctr
+		c.to(s.B, s.P)
+		if c.at == s.P {
+			return c.last
+		}
+	}
+	return s.Positions(s.P)[0]
+}
 
-func (s R) Pos() Position { return s.Positions(s.P)[0] }
+// counter incrementally counts the lines, columns, and runes of
+// a buffer one rune at a time and is shared by Positions and by Scan
+// (when Track is set) so that both always agree.
+type counter struct {
+	nls   []string // newline sequences, longest wins
+	files []File   // regions of buffer, line counts restart in each
+	file  int      // index of current File (-1 if none)
+	at    int      // byte offset just after last counted rune
+	nlend int      // end of newline sequence currently being counted
+	last  Position // of the last counted rune
+	line  int      // next line (0 until reset)
+	lbyte int      // next line column byte offset
+	lrune int      // next line column rune offset
+	brune int      // next overall rune offset
+	base  int      // bytes before the buffer (see R.Base)
+}
+
+func (c *counter) reset(nls []string, files []File) {
+	*c = counter{
+		nls: nls, files: files, file: -1,
+		line: 1, lbyte: 1, lrune: 1, brune: 1,
+	}
+}
+
+// count adds the rune (r) decoded from b[pp:p] to the counts. The runes
+// of a newline sequence all report the new line and column 0 so that
+// a position landing between CR and LF is never off by one.
+func (c *counter) count(b []byte, pp, p int, r rune) {
+	c.at = p
+	end := len(b)
+	for c.file+1 < len(c.files) && pp >= c.files[c.file+1].Off {
+		c.file++
+		c.line, c.lbyte, c.lrune, c.nlend = 1, 1, 1, 0
+	}
+	var name string
+	if c.file >= 0 {
+		name = c.files[c.file].Name
+	}
+	if c.file+1 < len(c.files) {
+		end = c.files[c.file+1].Off
+	}
+	if pp < c.nlend {
+		c.last = Position{
+			File: name, Rune: r, BufByte: c.base + p, BufRune: c.brune,
+			Line: c.line,
+		}
+		c.brune++
+		return
+	}
+	var nlen int
+	for _, nl := range c.nls {
+		if len(nl) > nlen && bytes.HasPrefix(b[pp:end], []byte(nl)) {
+			nlen = len(nl)
+		}
+	}
+	if nlen > 0 {
+		c.line++
+		c.nlend = pp + nlen
+		c.last = Position{
+			File: name, Rune: r, BufByte: c.base + p, BufRune: c.brune,
+			Line: c.line,
+		}
+		c.lbyte, c.lrune = 1, 1
+		c.brune++
+		return
+	}
+	c.last = Position{
+		File:    name,
+		Rune:    r,
+		BufByte: c.base + p,
+		BufRune: c.brune,
+		Line:    c.line,
+		LByte:   c.lbyte,
+		LRune:   c.lrune,
+	}
+	c.lbyte += p - pp
+	c.lrune++
+	c.brune++
+}
+
+// sync brings the counter up to the position (P) counting forward from
+// where it is (or from the beginning of the buffer when P has moved
+// back). This is only needed when the position (P) has been changed by
+// something other than Scan.
+func (c *counter) sync(s *R) {
+	if c.line > 0 && c.at == s.P {
+		return
+	}
+	if c.line == 0 || c.at > s.P {
+		*c = s.start()
+	}
+	c.to(s.B, s.P)
+}
+
+// to counts every rune up to the byte offset (p) returning the Position
+// of the first of them (which is the rune containing p when p lands in
+// the middle of one).
+func (c *counter) to(b []byte, p int) Position {
+	var first Position
+	for c.at < p && c.at < len(b) {
+		pp := c.at
+		r, ln := utf8.DecodeRune(b[pp:])
+		c.count(b, pp, pp+ln, r)
+		if first.BufByte == 0 {
+			first = c.last
+		}
+	}
+	return first
+}
+
+// start returns a counter ready to count from the beginning of the
+// buffer (which is not the beginning of the input when streaming).
+func (s R) start() counter {
+	if s.origin.line > 0 {
+		return s.origin
+	}
+	var c counter
+	c.reset(s.newlines(), s.Files)
+	return c
+}
+
+// newlines returns R.NewLine or scan.NewLine if unset.
+func (s R) newlines() []string {
+	if s.NewLine == nil {
+		return NewLine
+	}
+	return s.NewLine
+}
 
 // Positions returns human-friendly Position information (which can easily
 // be used to populate a text/template) for each raw byte offset (s.P).
@@ -146,7 +375,11 @@ func (s R) Pos() Position { return s.Positions(s.P)[0] }
 // runes since the raw byte position (s.P) is frequently changed
 // directly.  Therefore, when multiple positions are wanted, consider
 // caching the raw byte positions (s.P) and calling Positions() once for
-// all of them.
+// all of them. Offsets may be passed in any order and may be repeated.
+// The returned positions are always in the same order as the offsets
+// passed. An offset that lands inside of a multibyte rune (or newline
+// sequence) resolves to that rune. Offsets less than 1 (nothing scanned
+// yet) or beyond the end of the buffer are left as the zero Position.
 func (s R) Positions(p ...int) []Position {
 	pos := make([]Position, len(p))
 
@@ -154,45 +387,28 @@ func (s R) Positions(p ...int) []Position {
 		return pos
 	}
 
-	if s.NewLine == nil {
-		s.NewLine = []string{"\r\n", "\n"}
+	// indexes into p sorted by offset so that every offset can be
+	// resolved while walking the buffer only once
+	order := make([]int, 0, len(p))
+	for i, v := range p {
+		if v > 0 && v <= len(s.B) {
+			order = append(order, i)
+		}
 	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return p[order[a]] < p[order[b]]
+	})
 
-	_rune, line, lbyte, lrune := 1, 1, 1, 1
+	c := s.start()
 	_s := R{B: s.B}
-	//_s.Trace++
-
-	for _s.Scan() {
-
-		for _, nl := range s.NewLine {
-			if _s.Is(nl) {
-				line++
-				_s.P += len(nl) - 1
-				_rune += len(nl) - 1
-				lbyte = 0
-				lrune = 0
-				continue
-			}
-		}
+	next := 0
 
-		for i, v := range p {
-			if _s.P == v {
-				pos[i] = Position{
-					Rune:    _s.R,
-					BufByte: _s.P,
-					BufRune: _rune,
-					Line:    line,
-					LByte:   lbyte,
-					LRune:   lrune,
-				}
-			}
+	for next < len(order) && _s.Scan() {
+		c.count(s.B, _s.PP, _s.P, _s.R)
+		for next < len(order) && p[order[next]] <= _s.P {
+			pos[order[next]] = c.last
+			next++
 		}
-
-		rlen := len([]byte(string(s.R)))
-		lbyte += rlen
-		lrune++
-		_rune++
-
 	}
 
 	return pos
@@ -215,14 +431,58 @@ func (s R) String() string {
 // Print is shorthand for fmt.Println(s).
 func (s R) Print() { fmt.Println(s) }
 
-// Log is shorthand for log.Print(s).
-func (s R) Log() { log.Println(s) }
+// Log is shorthand for log.Print(s) unless Out is set in which case
+// it is written there instead.
+func (s R) Log() { s.output(s.String()) }
+
+// output writes the string (adding a line return if missing) to Out if
+// set or log.Print otherwise.
+func (s R) output(str string) { output(s.Out, str) }
+
+// output writes the string (adding a line return if missing) to w if
+// set or log.Print otherwise.
+func output(w io.Writer, str string) {
+	if w == nil {
+		log.Print(str)
+		return
+	}
+	if len(str) == 0 || str[len(str)-1] != '\n' {
+		str += "\n"
+	}
+	io.WriteString(w, str)
+}
 
 // Scan decodes the next rune, setting it to R, and advances position
 // (P) by the size of the rune (R) in bytes returning false then there
 // is nothing left to scan. Only runes bigger than utf8.RuneSelf are
 // decoded since most runes (ASCII) will usually be under this number.
+// Any Middleware (see Use) is called instead and wraps this behavior.
 func (s *R) Scan() bool {
+	if !s.hooked && !s.Track && s.Trace == 0 && Trace == 0 &&
+		s.P < len(s.B) && s.B[s.P] < utf8.RuneSelf {
+		s.PP = s.P
+		s.R = rune(s.B[s.P])
+		s.P++
+		return true
+	}
+	if s.mw != nil {
+		return s.mw(s)
+	}
+	return s.scan()
+}
+
+// hook records whether Scan has to do more than advance (Middleware,
+// breakpoints, watches, or streaming) so that when none are in use the
+// single check of hooked (along with Track and Trace) is all it costs.
+// Must be called whenever any of them changes.
+func (s *R) hook() {
+	s.hooked = s.mw != nil || s.src != nil || s.breakAt != nil || s.watches != nil
+}
+
+// scan is Scan without any Middleware.
+func (s *R) scan() bool {
+
+	s.ahead(utf8.UTFMax)
 
 	if s.P >= len(s.B) {
 		return false
@@ -230,28 +490,151 @@ func (s *R) Scan() bool {
 
 	ln := 1
 	r := rune(s.B[s.P])
-	if r > utf8.RuneSelf {
+	if r >= utf8.RuneSelf {
 		r, ln = utf8.DecodeRune(s.B[s.P:])
 		if ln == 0 {
 			return false
 		}
 	}
 
+	if s.Track {
+		s.ctr.sync(s)
+		s.ctr.count(s.B, s.P, s.P+ln, r)
+	}
+
 	s.PP = s.P
 	s.P += ln
 	s.R = r
 
+	if s.breakAt != nil && s.breakAt[s.P] {
+		s.breakpoint()
+	}
+
+	if s.watches != nil {
+		s.watch()
+	}
+
 	if s.Trace > 0 || Trace > 0 {
-		s.Log()
+		s.trace()
+	}
+
+	return true
+}
+
+// BreakAt sets breakpoints at the byte offsets such that when Scan
+// reaches any of them (P equals the offset) the Break function is
+// called. If Break is not set then tracing is activated (Trace) from
+// that point on instead. This makes finding the one bad decision in
+// a long trace much easier. Calling with no offsets clears them.
+func (s *R) BreakAt(offsets ...int) {
+	defer s.hook()
+	if len(offsets) == 0 {
+		s.breakAt = nil
+		return
+	}
+	if s.breakAt == nil {
+		s.breakAt = map[int]bool{}
+	}
+	for _, p := range offsets {
+		s.breakAt[p] = true
+	}
+}
+
+// BreakOn sets breakpoints for the Token types (see Rule) such that when
+// a Lexer produces one of them the Break function is called (or tracing
+// activated) just as with BreakAt. Calling with no types clears them.
+func (s *R) BreakOn(types ...int) {
+	if len(types) == 0 {
+		s.breakOn = nil
+		return
+	}
+	if s.breakOn == nil {
+		s.breakOn = map[int]bool{}
 	}
+	for _, t := range types {
+		s.breakOn[t] = true
+	}
+}
 
+func (s *R) breakpoint() {
+	if s.Break != nil {
+		s.contain("Break", func() { s.Break(s) })
+		return
+	}
+	if s.Trace == 0 {
+		s.Trace = 1
+	}
+}
+
+// ScanByte is the same as Scan but advances exactly one byte without
+// any rune decoding setting R to the rune equivalent of that byte. This
+// is useful for grammars mixing text and binary sections (tar-like
+// formats, network frames, and such).
+func (s *R) ScanByte() bool {
+	s.ahead(1)
+	if s.P >= len(s.B) {
+		return false
+	}
+	s.PP = s.P
+	s.R = rune(s.B[s.P])
+	s.P++
+	if s.Trace > 0 || Trace > 0 {
+		s.Log()
+	}
 	return true
 }
 
+// PeekByte returns the byte at the current position (P) without
+// advancing and false if there is nothing left.
+func (s *R) PeekByte() (byte, bool) {
+	s.ahead(1)
+	if s.P >= len(s.B) {
+		return 0, false
+	}
+	return s.B[s.P], true
+}
+
+// PeekBytes returns the next n bytes from the current position (P)
+// without advancing or nil if fewer than n remain. The returned slice
+// shares the buffer (B) and must not be modified.
+func (s *R) PeekBytes(n int) []byte {
+	s.ahead(n)
+	if n < 0 || s.P+n > len(s.B) {
+		return nil
+	}
+	return s.B[s.P : s.P+n]
+}
+
+// PeekRune returns the rune at the current position (P) without
+// advancing (the one the next Scan would set to R) or 0 if there is
+// nothing left. Invalid encodings return utf8.RuneError.
+func (s *R) PeekRune() rune {
+	s.ahead(utf8.UTFMax)
+	if s.P >= len(s.B) {
+		return 0
+	}
+	r, _ := utf8.DecodeRune(s.B[s.P:])
+	return r
+}
+
+// PeekN returns (a copy of) the next n runes from the current position
+// (P) without advancing or fewer if the end of the buffer is reached
+// first. Use PeekBytes when a number of bytes is wanted instead.
+func (s *R) PeekN(n int) string {
+	s.ahead(n * utf8.UTFMax)
+	end := s.P
+	for i := 0; i < n && end < len(s.B); i++ {
+		_, ln := utf8.DecodeRune(s.B[end:])
+		end += ln
+	}
+	return string(s.B[s.P:end])
+}
+
 // Peek returns true if the passed string matches from current position
 // in the buffer (s.P) forward. Returns false if the string
 // would go beyond the length of buffer (len(s.B)).
 func (s *R) Peek(a string) bool {
+	s.ahead(len(a))
 	if len(a)+s.P > len(s.B) {
 		return false
 	}
@@ -261,8 +644,109 @@ func (s *R) Peek(a string) bool {
 	return false
 }
 
+// ErrInvalidUTF8 is returned by ReadRune when the next bytes in the
+// buffer are not a valid UTF-8 encoding.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8 encoding")
+
+// ErrInvalidUnreadRune is returned by UnreadRune when there is nothing
+// before the current position (P) to unread.
+var ErrInvalidUnreadRune = errors.New("nothing to unread")
+
+// ReadRune fulfills the io.RuneReader interface for those who need
+// io-style error semantics instead of the bare bool of Scan (which it
+// otherwise calls). Returns io.EOF when nothing is left and
+// io.ErrUnexpectedEOF (without advancing) when the buffer ends in the
+// middle of a multibyte rune. Invalid encodings return
+// utf8.RuneError and ErrInvalidUTF8 but advance one byte so that
+// callers may choose to continue.
+func (s *R) ReadRune() (rune, int, error) {
+	s.ahead(utf8.UTFMax)
+	if s.P >= len(s.B) {
+		return 0, 0, io.EOF
+	}
+	if !utf8.FullRune(s.B[s.P:]) {
+		return utf8.RuneError, 0, io.ErrUnexpectedEOF
+	}
+	if r, ln := utf8.DecodeRune(s.B[s.P:]); r == utf8.RuneError && ln == 1 {
+		s.PP, s.R = s.P, r
+		s.P++
+		return r, 1, ErrInvalidUTF8
+	}
+	s.Scan()
+	return s.R, s.P - s.PP, nil
+}
+
+// UnreadRune fulfills the io.RuneScanner interface by moving the
+// position (P) back to the beginning of the last rune (R) and updating
+// both R and PP to the rune before it (if any). Unlike most UnreadRune
+// implementations it may be called repeatedly to keep moving back.
+// Returns ErrInvalidUnreadRune when already at the beginning.
+func (s *R) UnreadRune() error {
+	if s.P <= 0 {
+		return ErrInvalidUnreadRune
+	}
+	start := s.PP
+	if start >= s.P {
+		_, ln := utf8.DecodeLastRune(s.B[:s.P])
+		start = s.P - ln
+	}
+	s.goTo(start)
+	return nil
+}
+
+// ScanN calls Scan up to n times returning the number of runes actually
+// scanned (less than n only at the end of the buffer). This is useful
+// for fixed-width fields.
+func (s *R) ScanN(n int) int {
+	var i int
+	for i < n && s.Scan() {
+		i++
+	}
+	return i
+}
+
+// Unscan moves back n runes (walking rune boundaries so multibyte runes
+// are handled) as if the rune before the new position had just been
+// scanned (updating R and PP) and returns the number actually moved
+// back, which is less than n only when the beginning of the buffer is
+// reached. This is the simple fix when a helper has over-consumed by
+// a known number of runes. (Back restores a Mark instead.)
+func (s *R) Unscan(n int) int {
+	p := s.P
+	var i int
+	for ; i < n && p > 0; i++ {
+		_, ln := utf8.DecodeLastRune(s.B[:p])
+		p -= ln
+	}
+	s.goTo(p)
+	return i
+}
+
 // End returns true if scanner has nothing more to scan.
-func (s *R) End() bool { return s.P == len(s.B) }
+func (s *R) End() bool {
+	s.ahead(1)
+	return s.P == len(s.B)
+}
+
+// Remaining returns the number of bytes left to scan (useful for
+// progress reporting along with Consumed). When streaming only what has
+// been read into the window is counted.
+func (s *R) Remaining() int { return len(s.B) - s.P }
+
+// RemainingRunes is the same as Remaining but counts runes instead of
+// bytes (which requires a pass through the rest of the buffer). When
+// streaming only what has been read into the buffer is counted.
+func (s *R) RemainingRunes() int { return utf8.RuneCount(s.B[s.P:]) }
+
+// Consumed returns the number of bytes already scanned (same as P
+// unless streaming when it includes the bytes dropped, see Base) so
+// that it always refers to the whole input.
+func (s *R) Consumed() int { return s.Base + s.P }
+
+// Rest returns the part of the buffer left to scan (not a copy) for
+// grammars that need "rest of input" semantics. When streaming only what
+// has been read into the window is returned (see Stream).
+func (s *R) Rest() []byte { return s.B[s.P:] }
 
 // Mark returns the main state values in order to jump Back() when
 // required during other scan operations. Mark fulfills the pegn.Scanner
@@ -287,6 +771,114 @@ func (s *R) Is(a string) bool {
 	return false
 }
 
+// Expect advances past the first of the alternative strings to match
+// from the current position (see Peek) and returns true. Otherwise, it
+// adds a single Error listing every alternative (ex: "expected ',' or
+// ')'") so that user-facing messages read well without any extra work.
+func (s *R) Expect(alts ...string) bool {
+	for _, a := range alts {
+		if len(a) > 0 && s.Peek(a) {
+			s.goTo(s.P + len(a))
+			return true
+		}
+	}
+	s.Error(expected(alts))
+	return false
+}
+
+// Keyword advances past the keyword (kw) and returns true only if it
+// matches from the current position and is not immediately followed by
+// a rune in the continuation Class (cont) so that "return" does not
+// match the beginning of "returned". If cont is nil letters, digits,
+// and underscore continue an identifier.
+func (s *R) Keyword(kw string, cont *Class) bool {
+	s.ahead(len(kw) + utf8.UTFMax)
+	if len(kw) == 0 || !s.Peek(kw) {
+		return false
+	}
+	if end := s.P + len(kw); end < len(s.B) {
+		r, _ := utf8.DecodeRune(s.B[end:])
+		switch {
+		case cont != nil && cont.Contains(r):
+			return false
+		case cont == nil && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return false
+		}
+	}
+	s.goTo(s.P + len(kw))
+	return true
+}
+
+// SkipWhile scans past every rune for which the predicate (fn) returns
+// true (ex: unicode.IsSpace) and returns the number of runes skipped.
+// Scanning stops without consuming the first rune that does not
+// satisfy fn.
+func (s *R) SkipWhile(fn func(r rune) bool) int {
+	var n int
+	for !s.End() && fn(s.PeekRune()) && s.Scan() {
+		n++
+	}
+	return n
+}
+
+// TakeWhile is the same as SkipWhile but returns the text skipped
+// (a copy) making runs of digits, identifiers, and such trivial to
+// consume without a regular expression.
+func (s *R) TakeWhile(fn func(r rune) bool) string {
+	buf := new(strings.Builder)
+	for !s.End() && fn(s.PeekRune()) && s.Scan() {
+		buf.Write(s.B[s.PP:s.P])
+	}
+	return buf.String()
+}
+
+// ReadLine advances past the next line including its newline (the
+// longest of NewLine to match, see R.NewLine) and returns the line
+// without it. The last line need not end with a newline. Returns false
+// only when there is nothing left to read. This is handy for scanners
+// that mix line-oriented and rune-oriented sections.
+func (s *R) ReadLine() (string, bool) {
+	if s.End() {
+		return "", false
+	}
+	nls := s.newlines()
+	buf := new(strings.Builder)
+	for !s.End() {
+		var nl string
+		for _, n := range nls {
+			if len(n) > len(nl) && s.Peek(n) {
+				nl = n
+			}
+		}
+		if len(nl) > 0 {
+			s.goTo(s.P + len(nl))
+			break
+		}
+		s.Scan()
+		buf.Write(s.B[s.PP:s.P])
+	}
+	return buf.String(), true
+}
+
+// expected returns a message listing the alternatives.
+func expected(alts []string) string {
+	var list string
+	for i, a := range alts {
+		a = "'" + a + "'"
+		switch {
+		case i == 0:
+			list = a
+		case len(alts) == 2:
+			list = msg("%v or %v", list, a)
+		case i == len(alts)-1:
+			list = msg("%v, or %v", list, a)
+		default:
+			list += ", " + a
+		}
+	}
+	return msg("expected %v", list)
+}
+
 // PeekMatch checks for a regular expression match at the current
 // position in the buffer providing a mechanism for positive and
 // negative lookahead expressions. It returns the length of the match.
@@ -295,6 +887,7 @@ func (s *R) Is(a string) bool {
 // regular expressions now include the Unicode character classes (ex:
 // \p{L}) that should be used over dated alternatives (ex: \w).
 func (s *R) PeekMatch(re *regexp.Regexp) int {
+	s.ahead(s.window)
 	loc := re.FindIndex(s.B[s.P:])
 	if loc == nil {
 		return -1
@@ -313,6 +906,7 @@ func (s *R) PeekMatch(re *regexp.Regexp) int {
 // regular expressions now include the Unicode character classes (ex:
 // \p{L}) that should be used over dated alternatives (ex: \w).
 func (s *R) Match(re *regexp.Regexp) int {
+	s.ahead(s.window)
 	loc := re.FindIndex(s.B[s.PP:])
 	if loc == nil {
 		return -1
@@ -323,13 +917,46 @@ func (s *R) Match(re *regexp.Regexp) int {
 	return -1
 }
 
+// Behind returns true if the passed string matches the text ending
+// just before the last scanned rune (s.PP) providing a mechanism for
+// positive and negative lookbehind (ex: "preceded by"). When streaming
+// only the bytes kept behind the position (see Stream) can be matched.
+func (s *R) Behind(a string) bool {
+	return bytes.HasSuffix(s.B[:s.PP], []byte(a))
+}
+
+// BehindExp checks for a regular expression match ending just before
+// the last scanned rune (s.PP) and returns the length of the match
+// (which might be zero) or a negative value if none is found. The
+// expression should usually end with $ (ex: `\*+$`) so that the match
+// that ends at s.PP is the one found (the leftmost match otherwise
+// wins).
+func (s *R) BehindExp(re *regexp.Regexp) int {
+	loc := re.FindIndex(s.B[:s.PP])
+	if loc == nil || loc[1] != s.PP {
+		return -1
+	}
+	return loc[1] - loc[0]
+}
+
 // Report will fill in the s.Template (or scan.Template if not set) and
-// log it to standard error. See the log package for removing prefixes
-// and such. The DefaultTemplate is compiled at init() and assigned to
-// the scan.Template global package variable. To silence reports
-// developers may use the log package or simply ensure that both
-// s.Template and scan.Template are nil.
+// log it to standard error (or write it to s.Out when set). See the log
+// package for removing prefixes and such. The DefaultTemplate is
+// compiled at init() and assigned to the scan.Template global package
+// variable. To silence reports developers may use the log package or
+// simply ensure that both s.Template and scan.Template are nil. If JSON
+// is set the Diagnostic is reported as a single line of JSON instead
+// and the templates are ignored.
 func (s R) Report() {
+	if s.JSON {
+		buf, err := json.Marshal(s.Diagnostic())
+		if err != nil {
+			s.output(err.Error())
+			return
+		}
+		s.output(string(buf))
+		return
+	}
 	// TODO expand the s.Errors if no s.Position on first
 	tmpl := s.Template
 	if s.Template == nil {
@@ -340,16 +967,73 @@ func (s R) Report() {
 	}
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, s); err != nil {
-		log.Println(err)
+		s.output(err.Error())
 		return
 	}
-	log.Print(buf.String())
+	s.output(buf.String())
+}
+
+// Diagnostic is the machine-readable form of the scanner state reported
+// when JSON is set.
+type Diagnostic struct {
+	Pos    Position `json:"pos"`
+	Errors []Error  `json:"errors,omitempty"`
+}
+
+// Diagnostic returns the current Position and Errors (see Errs) in
+// a form suitable for marshaling.
+func (s R) Diagnostic() Diagnostic {
+	return Diagnostic{Pos: s.Pos(), Errors: s.Errs()}
+}
+
+// Errs returns the Errors (in order) as Error values so callers need
+// not know the types in the stack. Errors that are not of type Error
+// are converted with only their message and any Error with a byte
+// offset (P) but no Position has its Position populated (all in one
+// pass through the buffer). Returns nil if there are none.
+func (s R) Errs() []Error {
+	if len(s.Errors) == 0 {
+		return nil
+	}
+	list := make([]Error, len(s.Errors))
+	var need []int
+	for i, err := range s.Errors {
+		e, is := err.(Error)
+		if !is {
+			e = Error{Msg: err.Error()}
+		}
+		if e.Pos.Line == 0 && e.P > 0 {
+			need = append(need, i)
+		}
+		list[i] = e
+	}
+	if len(need) > 0 {
+		offs := make([]int, len(need))
+		for i, n := range need {
+			offs[i] = list[n].P
+		}
+		for i, pos := range s.Positions(offs...) {
+			list[need[i]].Pos = pos
+		}
+	}
+	return list
 }
 
+// LastError returns the most recent of the Errors or nil if none.
+func (s R) LastError() error {
+	if len(s.Errors) == 0 {
+		return nil
+	}
+	return s.Errors[len(s.Errors)-1]
+}
+
+// ClearErrors removes all the Errors (keeping their allocated capacity).
+func (s *R) ClearErrors() { s.Errors = s.Errors[:0] }
+
 type Error struct {
-	P   int      // can be left blank if Pos is defined
-	Pos Position // can be left blank, Report will populate
-	Msg string
+	P   int      `json:"p,omitempty"` // can be left blank if Pos is defined
+	Pos Position `json:"pos"`         // can be left blank, Report will populate
+	Msg string   `json:"msg"`
 }
 
 func (e Error) Error() string {
@@ -365,13 +1049,62 @@ func (e Error) Error() string {
 // efficiently before executing the template. For single errors, calling
 // this method should be fine.
 func (s *R) Error(a ...any) {
-	msg := DefaultErrorMessage
+	m := msg(DefaultErrorMessage)
 	switch {
 	case len(a) > 0:
-		msg, _ = a[0].(string)
+		m, _ = a[0].(string)
 	case len(a) > 1:
 		form, _ := a[0].(string)
-		msg = fmt.Sprintf(form, a[1:]...)
+		m = fmt.Sprintf(form, a[1:]...)
 	}
-	s.Errors = append(s.Errors, Error{Pos: s.Pos(), Msg: msg})
+	s.Errors = append(s.Errors, Error{Pos: s.Pos(), Msg: m})
+	if (s.Trace|Trace)&TraceColor != 0 {
+		s.output(red + "error: " + s.Errors[len(s.Errors)-1].Error() + reset)
+	}
+}
+
+// Assert calls the predicate (fn) and, when it returns false, adds an
+// Error with the message (see Error) at the current position. Assert
+// restores the position after calling fn (even if it scans) and is
+// meant for embedding invariants into hand-written grammars while
+// developing them to get precise locations of failures. A panicking
+// predicate adds an Error for the panic instead (as do the other hooks:
+// Break, Watch, Fold, Rule.Value, and Pipeline.Parse).
+func (s *R) Assert(fn func(s *R) bool, msg string) bool {
+	var ok bool
+	m := s.Ptr()
+	err := contain("Assert", func() { ok = fn(s) })
+	s.Goto(m)
+	if err != nil {
+		s.Errors = append(s.Errors, Error{Pos: s.Pos(), Msg: err.Error()})
+		return false
+	}
+	if ok {
+		return true
+	}
+	s.Error(msg)
+	return false
+}
+
+// contain calls the hook function (fn) converting any panic into an
+// Error at the current position (added to Errors) so that one buggy
+// hook does not crash everything using the scanner. Returns false if
+// fn panicked.
+func (s *R) contain(hook string, fn func()) bool {
+	err := contain(hook, fn)
+	if err != nil {
+		s.Errors = append(s.Errors, Error{Pos: s.Pos(), Msg: err.Error()})
+	}
+	return err == nil
+}
+
+// contain calls fn returning any panic as an error naming the hook.
+func contain(hook string, fn func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = errors.New(msg("panic in %v: %v", hook, v))
+		}
+	}()
+	fn()
+	return nil
 }
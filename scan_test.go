@@ -2,9 +2,13 @@ package scan_test
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"regexp"
+	"strings"
+	"testing"
+	"unicode"
 
 	"github.com/rwxrob/scan"
 )
@@ -20,6 +24,30 @@ func ExampleR_init() {
 
 }
 
+func ExampleR_Reset() {
+	s := new(scan.R)
+	s.Buffer("foo")
+	s.Scan()
+	s.Error("sample error")
+	s.Print()
+	fmt.Println(len(s.Errors))
+
+	s.Reset()
+	s.Print()
+	fmt.Println(len(s.Errors))
+
+	s.Buffer("bar")
+	s.Reset()
+	s.Print()
+
+	// Output:
+	// 1 'f' "oo"
+	// 1
+	// 0 '\x00' "foo"
+	// 0
+	// 0 '\x00' "bar"
+}
+
 func ExampleR_Scan() {
 	s := new(scan.R)
 	s.B = []byte(`foo`)
@@ -79,6 +107,95 @@ func ExampleR_Scan_jump() {
 
 }
 
+func ExampleR_ReadRune() {
+	s := new(scan.R)
+	s.B = []byte("a👿\xffb\xf0\x9f")
+
+	for {
+		r, n, err := s.ReadRune()
+		fmt.Printf("%q %v %v\n", r, n, err)
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			break
+		}
+	}
+
+	fmt.Println(s.UnreadRune())
+	fmt.Println(s.UnreadRune())
+	s.Print()
+	fmt.Println(s.UnreadRune(), s.UnreadRune(), s.UnreadRune())
+	s.Print()
+
+	// Output:
+	// 'a' 1 <nil>
+	// '👿' 4 <nil>
+	// '�' 1 invalid UTF-8 encoding
+	// 'b' 1 <nil>
+	// '�' 0 unexpected EOF
+	// <nil>
+	// <nil>
+	// 5 '👿' "\xffb\xf0\x9f"
+	// <nil> <nil> nothing to unread
+	// 0 '\x00' "a👿\xffb\xf0\x9f"
+}
+
+func ExampleR_ScanN() {
+	s := new(scan.R)
+	s.B = []byte("2024👿06")
+	fmt.Println(s.ScanN(4), string(s.B[:s.P]))
+	s.Print()
+	fmt.Println(s.ScanN(5))
+	s.Print()
+
+	// Output:
+	// 4 2024
+	// 4 '4' "👿06"
+	// 3
+	// 10 '6' ""
+}
+
+func ExampleR_Unscan() {
+	s := new(scan.R)
+	s.B = []byte("a👿bc")
+	for s.Scan() {
+	}
+	s.Print()
+	fmt.Println(s.Unscan(2))
+	s.Print()
+	fmt.Println(s.Unscan(5))
+	s.Print()
+
+	// Output:
+	// 7 'c' ""
+	// 2
+	// 5 '👿' "bc"
+	// 2
+	// 0 '\x00' "a👿bc"
+}
+
+func ExampleR_ScanByte() {
+	s := new(scan.R)
+	s.B = []byte("\x00\x02hi👿")
+
+	fmt.Println(s.PeekBytes(2))
+	s.ScanByte()
+	s.ScanByte()
+	fmt.Println(s.R)
+	fmt.Println(string(s.PeekBytes(int(s.R))))
+	fmt.Println(s.PeekBytes(20))
+	s.P += int(s.R)
+	fmt.Println(s.PeekByte())
+	s.ScanByte()
+	s.Print()
+
+	// Output:
+	// [0 2]
+	// 2
+	// hi
+	// []
+	// 240 true
+	// 5 'ð' "\x9f\x91\xbf"
+}
+
 func ExampleR_Is() {
 	s := new(scan.R)
 	s.B = []byte(`foo`)
@@ -172,6 +289,54 @@ func ExampleR_Pos() {
 
 }
 
+func ExampleR_Pos_track() {
+	s := new(scan.R)
+	s.B = []byte("one\r\ntwo")
+	s.Track = true
+
+	for s.Scan() {
+		s.Pos().Print()
+	}
+
+	s.P = 2 // changed directly, not by Scan
+	s.Pos().Print()
+
+	// Output:
+	// U+006F 'o' 1,1-1 (1-1)
+	// U+006E 'n' 1,2-2 (2-2)
+	// U+0065 'e' 1,3-3 (3-3)
+	// U+000D '\r' 2,0-0 (4-4)
+	// U+000A '\n' 2,0-0 (5-5)
+	// U+0074 't' 2,1-1 (6-6)
+	// U+0077 'w' 2,2-2 (7-7)
+	// U+006F 'o' 2,3-3 (8-8)
+	// U+006E 'n' 1,2-2 (2-2)
+
+}
+
+func ExampleR_Pos_trackReadLine() {
+	s := new(scan.R)
+	s.B = []byte("one\ntwo 👿\nthree")
+	s.Track = true
+
+	// lines and backtracking move P without Scan but are counted forward
+	s.ReadLine()
+	r, p, pp := s.Mark()
+	s.ScanN(5)
+	s.Pos().Print()
+	s.Back(r, p, pp)
+	s.Scan()
+	s.Pos().Print()
+	s.ReadLine()
+	s.Scan()
+	s.Pos().Print()
+
+	// Output:
+	// U+1F47F '👿' 2,5-5 (9-12)
+	// U+0074 't' 2,1-1 (5-5)
+	// U+0074 't' 3,1-1 (11-14)
+}
+
 func ExampleR_Positions() {
 	s := new(scan.R)
 	s.B = []byte("one line\nand another\r\nand yet another")
@@ -187,6 +352,86 @@ func ExampleR_Positions() {
 
 }
 
+func ExampleR_Positions_unsorted() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand another\r\nand yet another")
+
+	for _, p := range s.Positions(27, 2, 12, 2, 0, 21) {
+		p.Print()
+	}
+
+	// Output:
+	// U+0079 'y' 3,5-5 (27-27)
+	// U+006E 'n' 1,2-2 (2-2)
+	// U+0064 'd' 2,3-3 (12-12)
+	// U+006E 'n' 1,2-2 (2-2)
+	// U+0000 '\x00' 0,0-0 (0-0)
+	// U+000D '\r' 3,0-0 (21-21)
+
+}
+
+func ExampleR_Positions_multibyte() {
+	s := new(scan.R)
+	s.B = []byte("a👿b")
+
+	for _, p := range s.Positions(1, 3, 5, 6) {
+		p.Print()
+	}
+
+	// Output:
+	// U+0061 'a' 1,1-1 (1-1)
+	// U+1F47F '👿' 1,2-2 (2-5)
+	// U+1F47F '👿' 1,2-2 (2-5)
+	// U+0062 'b' 1,3-6 (3-6)
+
+}
+
+func ExampleR_Positions_crlf() {
+	s := new(scan.R)
+	s.B = []byte("a\r\nb\rc\nd")
+
+	// order does not matter, longest sequence wins
+	s.NewLine = []string{"\n", "\r", "\r\n"}
+	for _, p := range s.Positions(2, 3, 4, 6, 8, 9) {
+		p.Print()
+	}
+
+	// CR and LF counted separately
+	s.NewLine = []string{"\r", "\n"}
+	for _, p := range s.Positions(3, 4) {
+		p.Print()
+	}
+
+	// Output:
+	// U+000D '\r' 2,0-0 (2-2)
+	// U+000A '\n' 2,0-0 (3-3)
+	// U+0062 'b' 2,1-1 (4-4)
+	// U+0063 'c' 3,1-1 (6-6)
+	// U+0064 'd' 4,1-1 (8-8)
+	// U+0000 '\x00' 0,0-0 (0-0)
+	// U+000A '\n' 3,0-0 (3-3)
+	// U+0062 'b' 3,1-1 (4-4)
+
+}
+
+func ExampleR_Positions_unicode() {
+	s := new(scan.R)
+	s.B = []byte("a\u2028b\u2029c\u0085d")
+	s.NewLine = scan.UnicodeNewLine
+
+	for _, p := range s.Positions(1, 4, 5, 9, 12) {
+		p.Print()
+	}
+
+	// Output:
+	// U+0061 'a' 1,1-1 (1-1)
+	// U+2028 '\u2028' 2,0-0 (2-4)
+	// U+0062 'b' 2,1-1 (3-5)
+	// U+0063 'c' 3,1-1 (5-9)
+	// U+0064 'd' 4,1-1 (7-12)
+
+}
+
 func ExampleR_Report() {
 	defer log.SetFlags(log.Flags())
 	defer log.SetOutput(os.Stderr)
@@ -212,6 +457,26 @@ func ExampleR_Report() {
 
 }
 
+func ExampleR_Report_json() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand another")
+	s.Out = os.Stdout
+	s.JSON = true
+
+	s.Scan()
+	s.Report()
+
+	s.P = 12
+	s.Error("sample error")
+	s.Errors = append(s.Errors, scan.Error{P: 3, Msg: "by offset"})
+	s.Report()
+
+	// Output:
+	// {"pos":{"rune":111,"byte":1,"brune":1,"line":1,"lbyte":1,"lrune":1}}
+	// {"pos":{"rune":100,"byte":12,"brune":12,"line":2,"lbyte":3,"lrune":3},"errors":[{"pos":{"rune":100,"byte":12,"brune":12,"line":2,"lbyte":3,"lrune":3},"msg":"sample error"},{"p":3,"pos":{"rune":101,"byte":3,"brune":3,"line":1,"lbyte":3,"lrune":3},"msg":"by offset"}]}
+
+}
+
 func ExampleR_End() {
 	s := new(scan.R)
 	s.B = []byte("foo")
@@ -234,3 +499,272 @@ func ExampleR_End() {
 	// 3 'o' ""
 	// true
 }
+
+func ExampleR_Remaining() {
+	s := new(scan.R)
+	s.B = []byte("fo👿")
+	for s.Scan() {
+		fmt.Println(s.Consumed(), s.Remaining(), s.RemainingRunes(), string(s.Rest()))
+	}
+
+	// Output:
+	// 1 5 2 o👿
+	// 2 4 1 👿
+	// 6 0 0
+}
+
+func ExampleR_Assert() {
+	s := new(scan.R)
+	s.B = []byte("ab1")
+
+	letter := func(s *scan.R) bool { return unicode.IsLetter(s.R) }
+	for s.Scan() {
+		s.Assert(letter, "expected letter")
+	}
+	fmt.Println(s.Errors)
+
+	// Output:
+	// [expected letter at U+0031 '1' 1,3-3 (3-3)]
+}
+
+func ExampleR_Assert_panic() {
+	s := new(scan.R)
+	s.B = []byte("ab")
+
+	var seen []rune
+	buggy := func(s *scan.R) bool { return seen[len(seen)-1] != s.R }
+	for s.Scan() {
+		s.Assert(buggy, "repeated rune")
+		seen = append(seen, s.R)
+	}
+	fmt.Println(s.Errors)
+
+	// Output:
+	// [panic in Assert: runtime error: index out of range [-1] at U+0061 'a' 1,1-1 (1-1)]
+}
+
+func ExampleR_Assert_scans() {
+	s := new(scan.R)
+	s.B = []byte("ab")
+
+	nextIsA := func(s *scan.R) bool { return s.Scan() && s.R == 'a' }
+	fmt.Println(s.Assert(nextIsA, "expected a"), s.P)
+	s.Scan()
+	fmt.Println(s.Assert(nextIsA, "expected a"), s.P)
+	fmt.Println(s.Errors)
+
+	// Output:
+	// true 0
+	// false 1
+	// [expected a at U+0061 'a' 1,1-1 (1-1)]
+}
+
+func ExampleR_BreakAt() {
+	s := new(scan.R)
+	s.B = []byte("some thing")
+	s.Break = func(s *scan.R) { fmt.Print("break: "); s.Print() }
+	s.BreakAt(3, 7)
+	for s.Scan() {
+	}
+
+	s.Buffer("foo")
+	s.Break = nil
+	s.Out = os.Stdout
+	s.BreakAt()
+	s.BreakAt(2)
+	for s.Scan() {
+	}
+
+	// Output:
+	// break: 3 'm' "e thing"
+	// break: 7 'h' "ing"
+	// 2 'o' "o"
+	// 3 'o' ""
+}
+
+func ExampleR_Errs() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand another")
+	fmt.Println(s.LastError(), s.Errs())
+
+	s.P = 12
+	s.Error("sample error")
+	s.Errors = append(s.Errors, scan.Error{P: 3, Msg: "by offset"})
+	s.Errors = append(s.Errors, io.ErrUnexpectedEOF)
+
+	for _, e := range s.Errs() {
+		fmt.Println(e.Msg, "|", e.Pos)
+	}
+	fmt.Println(s.LastError())
+
+	s.ClearErrors()
+	fmt.Println(s.LastError(), len(s.Errors))
+
+	// Output:
+	// <nil> []
+	// sample error | U+0064 'd' 2,3-3 (12-12)
+	// by offset | U+0065 'e' 1,3-3 (3-3)
+	// unexpected EOF | U+0000 '\x00' 0,0-0 (0-0)
+	// unexpected EOF
+	// <nil> 0
+}
+
+func ExampleR_Expect() {
+	s := new(scan.R)
+	s.B = []byte("f(a,b]")
+
+	s.Scan()
+	fmt.Println(s.Expect("("))
+	s.Scan()
+	fmt.Println(s.Expect(",", ")"))
+	s.Scan()
+	fmt.Println(s.Expect(",", ")"))
+	fmt.Println(s.Expect(",", ")", "..."))
+	fmt.Println(s.Errors)
+
+	// Output:
+	// true
+	// true
+	// false
+	// false
+	// [expected ',' or ')' at U+0062 'b' 1,5-5 (5-5) expected ',', ')', or '...' at U+0062 'b' 1,5-5 (5-5)]
+}
+
+func ExampleR_Keyword() {
+	s := new(scan.R)
+	for _, in := range []string{"return x", "returned", "return_", "return", "if(x)", "if-x"} {
+		s.Buffer(in)
+		fmt.Println(s.Keyword("return", nil), s.Keyword("if", nil), s.P)
+	}
+
+	// with a custom continuation class dashes continue an identifier
+	kebab := new(scan.Class).AddRange('a', 'z').Add("-")
+	s.Buffer("if-x")
+	fmt.Println(s.Keyword("if", kebab))
+
+	// Output:
+	// true false 6
+	// false false 0
+	// false false 0
+	// true false 6
+	// false true 2
+	// false true 2
+	// false
+}
+
+func ExampleR_PeekRune() {
+	s := new(scan.R)
+	s.B = []byte("👿go")
+	fmt.Printf("%q %q %q\n", s.PeekRune(), s.PeekN(2), s.PeekN(10))
+	s.Scan()
+	fmt.Printf("%q %q\n", s.PeekRune(), s.PeekN(1))
+	s.ScanN(2)
+	fmt.Printf("%q %q\n", s.PeekRune(), s.PeekN(1))
+	s.Print()
+
+	// Output:
+	// '👿' "👿g" "👿go"
+	// 'g' "g"
+	// '\x00' ""
+	// 6 'o' ""
+}
+
+func ExampleR_Behind() {
+	s := new(scan.R)
+	s.B = []byte("a **b** c*")
+	stars := regexp.MustCompile(`\*+$`)
+	for s.Scan() {
+		if s.R == '*' || s.R == 'b' {
+			fmt.Println(s.PP, s.Behind(" "), s.Behind("**"), s.BehindExp(stars))
+		}
+	}
+
+	// Output:
+	// 2 true false -1
+	// 3 false false 1
+	// 4 false true 2
+	// 5 false false -1
+	// 6 false false 1
+	// 9 false false -1
+}
+
+func ExampleR_ReadLine() {
+	s := new(scan.R)
+	s.B = []byte("one\r\n\nthree\u2028four")
+	for {
+		line, ok := s.ReadLine()
+		if !ok {
+			break
+		}
+		fmt.Printf("%q\n", line)
+	}
+
+	s.Buffer("three\u2028four\n")
+	s.NewLine = scan.UnicodeNewLine
+	for line, ok := s.ReadLine(); ok; line, ok = s.ReadLine() {
+		fmt.Printf("%q\n", line)
+	}
+
+	// Output:
+	// "one"
+	// ""
+	// "three\u2028four"
+	// "three"
+	// "four"
+}
+
+func ExampleR_TakeWhile() {
+	s := new(scan.R)
+	s.B = []byte("  \tcount42 = 7")
+	fmt.Println(s.SkipWhile(unicode.IsSpace))
+	fmt.Printf("%q\n", s.TakeWhile(unicode.IsLetter))
+	fmt.Printf("%q\n", s.TakeWhile(unicode.IsLetter))
+	fmt.Printf("%q\n", s.TakeWhile(unicode.IsDigit))
+	s.Print()
+
+	// Output:
+	// 3
+	// "count"
+	// ""
+	// "42"
+	// 10 '2' " = 7"
+}
+
+// On ASCII the two are about the same (Scan skips decoding runes
+// below utf8.RuneSelf) but ScanByte moves through multibyte UTF-8
+// about 1.3 to 1.6 times as many bytes per second since it never
+// decodes at all.
+var benchInput = []struct{ name, text string }{
+	{"ascii", strings.Repeat("key = value, other=42\n", 4096)},
+	{"utf8", strings.Repeat("ключ = значение 👿\n", 4096)},
+}
+
+func BenchmarkR_Scan(b *testing.B) {
+	for _, in := range benchInput {
+		buf := []byte(in.text)
+		b.Run(in.name, func(b *testing.B) {
+			s := new(scan.R)
+			b.SetBytes(int64(len(buf)))
+			for range b.N {
+				s.Buffer(buf)
+				for s.Scan() {
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkR_ScanByte(b *testing.B) {
+	for _, in := range benchInput {
+		buf := []byte(in.text)
+		b.Run(in.name, func(b *testing.B) {
+			s := new(scan.R)
+			b.SetBytes(int64(len(buf)))
+			for range b.N {
+				s.Buffer(buf)
+				for s.ScanByte() {
+				}
+			}
+		})
+	}
+}
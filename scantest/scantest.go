@@ -0,0 +1,132 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package scantest keeps grammars honest across a corpus of real-world
+samples. Each input file in a directory is paired with a file of the
+same name plus the .want extension containing the expected output
+(usually the tokens, tree, or diagnostics) of a function run against
+a scanner with the input opened (see scan.R.Open).
+*/
+package scantest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/scan"
+)
+
+// WantExt is the extension added to the name of each input file for
+// the file containing its expected output.
+const WantExt = ".want"
+
+// Update, when true, makes Run write the actual output to the .want
+// files instead of comparing (usually set from a test flag).
+var Update bool
+
+// Mismatch is an input whose output differs from that wanted.
+type Mismatch struct {
+	File string // path of the input file
+	Diff string // line diff of wanted (-) and got (+), see Diff
+}
+
+// Run calls fn for every input file in dir (every regular file without
+// the WantExt extension, in name order) with a new scanner opened to
+// the file and compares what it returns to the content of the matching
+// .want file (missing ones are treated as empty). Returns any
+// mismatches and the first error reading or writing files.
+func Run(dir string, fn func(s *scan.R) string) ([]Mismatch, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var list []Mismatch
+	for _, e := range entries {
+		if !e.Type().IsRegular() || filepath.Ext(e.Name()) == WantExt {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		s := new(scan.R)
+		if err := s.Open(path); err != nil {
+			return list, err
+		}
+		got := fn(s)
+		if Update {
+			if err := os.WriteFile(path+WantExt, []byte(got), 0644); err != nil {
+				return list, err
+			}
+			continue
+		}
+		want, err := os.ReadFile(path + WantExt)
+		if err != nil && !os.IsNotExist(err) {
+			return list, err
+		}
+		if string(want) != got {
+			list = append(list, Mismatch{path, Diff(string(want), got)})
+		}
+	}
+	return list, nil
+}
+
+// Corpus is Run for tests reporting each Mismatch (and any error) as
+// a test error.
+func Corpus(t testing.TB, dir string, fn func(s *scan.R) string) {
+	t.Helper()
+	list, err := Run(dir, fn)
+	for _, m := range list {
+		t.Errorf("%v: mismatch (-want +got):\n%v", m.File, m.Diff)
+	}
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// Diff returns the lines of want and got that differ (prefixed with
+// - and + respectively) along with the line numbers (of want) where
+// each difference begins. Lines common to both are omitted. Returns an
+// empty string if they are the same.
+func Diff(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	// longest common subsequence lengths from the ends
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var buf strings.Builder
+	var hunk bool
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+			hunk = false
+			continue
+		case !hunk:
+			fmt.Fprintf(&buf, "@@ line %v\n", i+1)
+			hunk = true
+		}
+		if j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1] {
+			fmt.Fprintf(&buf, "-%v\n", a[i])
+			i++
+		} else {
+			fmt.Fprintf(&buf, "+%v\n", b[j])
+			j++
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,75 @@
+package scantest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/scan"
+	"github.com/rwxrob/scan/scantest"
+)
+
+var lexer, names = scan.MustParseLexer(`
+-space \s+
+Num    \d+
+Word   \p{L}+
+Op     [-+*/=]
+`)
+
+// buggy only matches single digit numbers
+var buggy, _ = scan.MustParseLexer(`
+-space \s+
+Num    \d
+Word   \p{L}+
+Op     [-+*/=]
+`)
+
+func tokens(s *scan.R) string { return lex(lexer, s) }
+
+func lex(lexer scan.Lexer, s *scan.R) string {
+	var buf strings.Builder
+	for t, err := range lexer.Tokens(s) {
+		if err != nil {
+			fmt.Fprintln(&buf, "error:", err)
+			break
+		}
+		fmt.Fprintf(&buf, "%v %q\n", names[t.Type], t.Text)
+	}
+	return buf.String()
+}
+
+func ExampleRun() {
+	list, err := scantest.Run("testdata/corpus", tokens)
+	fmt.Println(len(list), err)
+
+	list, err = scantest.Run("testdata/corpus", func(s *scan.R) string {
+		return lex(buggy, s)
+	})
+	for _, m := range list {
+		fmt.Print(m.File, "\n", m.Diff)
+	}
+	fmt.Println(err)
+
+	// Output:
+	// 0 <nil>
+	// testdata/corpus/b.txt
+	// @@ line 3
+	// -Num "22"
+	// +Num "2"
+	// +Num "2"
+	// <nil>
+}
+
+func ExampleDiff() {
+	fmt.Print(scantest.Diff("a\nb\nc\nd", "a\nc\nx\nd\ne"))
+
+	// Output:
+	// @@ line 2
+	// -b
+	// @@ line 4
+	// +x
+	// @@ line 5
+	// +e
+}
+
+func TestCorpus(t *testing.T) { scantest.Corpus(t, "testdata/corpus", tokens) }
@@ -0,0 +1,38 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+// Shrink returns a minimal version of the input for which fails still
+// returns true (usually by running a grammar and checking for an Error)
+// by repeatedly removing ever smaller chunks (halves, then quarters,
+// and so on down to single bytes) while the failure is preserved. A
+// panic in fails counts as failing so that inputs crashing a grammar
+// can be shrunk as well. The input itself is never changed and is
+// returned unchanged if it does not fail to begin with.
+func Shrink(input []byte, fails func(in []byte) bool) []byte {
+	try := func(in []byte) bool {
+		var failed bool
+		if contain("Shrink", func() { failed = fails(in) }) != nil {
+			return true
+		}
+		return failed
+	}
+	cur := append([]byte(nil), input...)
+	if !try(cur) {
+		return input
+	}
+	for n := len(cur) / 2; n > 0; n /= 2 {
+		for removed := true; removed; {
+			removed = false
+			for i := 0; i+n <= len(cur); i += n {
+				cand := append(append([]byte(nil), cur[:i]...), cur[i+n:]...)
+				if try(cand) {
+					cur, removed = cand, true
+					i -= n
+				}
+			}
+		}
+	}
+	return cur
+}
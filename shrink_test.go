@@ -0,0 +1,34 @@
+package scan_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleShrink() {
+	input := []byte(strings.Repeat("let x = 1\n", 20) + "y = 2 $ 3\n" +
+		strings.Repeat("let z = 4\n", 20))
+
+	fails := func(in []byte) bool {
+		s := new(scan.R)
+		s.Buffer(in)
+		_, err := lexer.Lex(s)
+		return err != nil
+	}
+	fmt.Printf("%v %q\n", len(input), scan.Shrink(input, fails))
+
+	// a panicking grammar is shrunk the same way
+	crashes := func(in []byte) bool {
+		if strings.Contains(string(in), "((") {
+			panic("too deep")
+		}
+		return false
+	}
+	fmt.Printf("%q\n", scan.Shrink([]byte("a + (b * ((c)))"), crashes))
+
+	// Output:
+	// 410 "$"
+	// "(("
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+// Span is a region of a buffer from the beginning byte offset (B) up to
+// but not including the ending byte offset (E) used everywhere a pair
+// of offsets would otherwise be needed.
+type Span struct {
+	B int `json:"b"` // index in buffer of beginning
+	E int `json:"e"` // index in buffer just after end
+}
+
+// Text returns the text of the buffer within the Span or an empty
+// string if the Span does not fit within it.
+func (sp Span) Text(buf []byte) string {
+	if sp.B < 0 || sp.E > len(buf) || sp.B > sp.E {
+		return ""
+	}
+	return string(buf[sp.B:sp.E])
+}
+
+// Len returns the number of bytes within the Span.
+func (sp Span) Len() int { return sp.E - sp.B }
+
+// Contains returns true if the byte offset (p) is within the Span.
+func (sp Span) Contains(p int) bool { return sp.B <= p && p < sp.E }
+
+// Union returns the smallest Span containing both Spans.
+func (sp Span) Union(o Span) Span {
+	if o.B < sp.B {
+		sp.B = o.B
+	}
+	if o.E > sp.E {
+		sp.E = o.E
+	}
+	return sp
+}
+
+// Last returns the Span of the last rune scanned (R) which is useful
+// for marking positions to be used later with Between.
+func (s R) Last() Span { return Span{s.PP, s.P} }
+
+// Inclusivity determines which edges of two Spans are used by Between.
+// The first letter is for the first Span and the second for the second
+// with B meaning its beginning and E its end. Therefore, BE includes
+// both Spans, EB excludes both, BB includes only the first, and EE
+// includes only the second.
+type Inclusivity int
+
+const (
+	BB Inclusivity = iota // first included, second excluded
+	BE                    // both included
+	EB                    // both excluded
+	EE                    // first excluded, second included
+)
+
+// Between returns the text of the buffer between the two Spans (usually
+// from Last) as determined by the Inclusivity. An empty string is
+// returned if the resulting region is out of order or does not fit
+// within the buffer.
+func (s R) Between(m1, m2 Span, in Inclusivity) string {
+	var sp Span
+	switch in {
+	case BB:
+		sp = Span{m1.B, m2.B}
+	case BE:
+		sp = Span{m1.B, m2.E}
+	case EB:
+		sp = Span{m1.E, m2.B}
+	case EE:
+		sp = Span{m1.E, m2.E}
+	}
+	return sp.Text(s.B)
+}
+
+// View returns the bytes of the buffer within the Span with capacity
+// limited to its length so that appending to it always copies rather
+// than overwriting the rest of the buffer. The bytes themselves are
+// shared and must not be modified (see Copy). Returns nil if the Span
+// does not fit the buffer.
+func (s R) View(sp Span) []byte {
+	if sp.B < 0 || sp.E > len(s.B) || sp.B > sp.E {
+		return nil
+	}
+	return s.B[sp.B:sp.E:sp.E]
+}
+
+// Copy returns a copy of the bytes of the buffer within the Span that
+// may be modified freely. Returns nil if the Span does not fit.
+func (s R) Copy(sp Span) []byte {
+	v := s.View(sp)
+	if v == nil {
+		return nil
+	}
+	return append([]byte(nil), v...)
+}
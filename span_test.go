@@ -0,0 +1,70 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleSpan() {
+	buf := []byte("some thing")
+	a := scan.Span{B: 0, E: 4}
+	b := scan.Span{B: 5, E: 10}
+
+	fmt.Printf("%q %q\n", a.Text(buf), b.Text(buf))
+	fmt.Println(a.Len(), b.Len())
+	fmt.Println(a.Contains(0), a.Contains(4), b.Contains(9))
+	fmt.Printf("%q\n", a.Union(b).Text(buf))
+	fmt.Printf("%q\n", scan.Span{B: 5, E: 20}.Text(buf))
+
+	// Output:
+	// "some" "thing"
+	// 4 5
+	// true false true
+	// "some thing"
+	// ""
+}
+
+func ExampleR_Between() {
+	s := new(scan.R)
+	s.B = []byte("foo(bar)baz")
+
+	for s.Scan() && s.R != '(' {
+	}
+	m1 := s.Last()
+	for s.Scan() && s.R != ')' {
+	}
+	m2 := s.Last()
+
+	fmt.Printf("%q\n", s.Between(m1, m2, scan.BB))
+	fmt.Printf("%q\n", s.Between(m1, m2, scan.BE))
+	fmt.Printf("%q\n", s.Between(m1, m2, scan.EB))
+	fmt.Printf("%q\n", s.Between(m1, m2, scan.EE))
+	fmt.Printf("%q\n", s.Between(m2, m1, scan.BE))
+
+	// Output:
+	// "(bar"
+	// "(bar)"
+	// "bar"
+	// "bar)"
+	// ""
+}
+
+func ExampleR_View() {
+	s := new(scan.R)
+	s.B = []byte("some thing")
+
+	v := s.View(scan.Span{B: 0, E: 4})
+	v = append(v, '!')
+	fmt.Printf("%s %s\n", v, s.B)
+
+	c := s.Copy(scan.Span{B: 5, E: 10})
+	c[0] = 'T'
+	fmt.Printf("%s %s\n", c, s.B)
+	fmt.Println(s.View(scan.Span{B: 5, E: 20}) == nil)
+
+	// Output:
+	// some! some thing
+	// Thing some thing
+	// true
+}
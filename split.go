@@ -0,0 +1,46 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+)
+
+// SplitFunc returns a bufio.SplitFunc that tokenizes a stream into
+// consecutive matches of the regular expression (re) so that tokens
+// defined by an expression can be used with the familiar bufio.Scanner
+// streaming model. Every token must begin exactly where the last ended
+// (use alternation to include separators and check the token text).
+// A match that reaches the end of the data read so far is not returned
+// until more has been read (or EOF) since it might continue to match.
+// Returns an error if nothing (or only an empty string) matches.
+func SplitFunc(re *regexp.Regexp) bufio.SplitFunc {
+	anchored := regexp.MustCompile(`^(?:` + re.String() + `)`)
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		loc := anchored.FindIndex(data)
+		if loc == nil || loc[1] == len(data) {
+			if !atEOF {
+				return 0, nil, nil
+			}
+		}
+		if loc == nil || loc[1] == 0 {
+			return 0, nil, fmt.Errorf("%v: %q does not match %q",
+				msg(DefaultErrorMessage), re, trim(data))
+		}
+		return loc[1], data[:loc[1]], nil
+	}
+}
+
+// trim returns no more than ViewLen bytes of the buffer.
+func trim(b []byte) []byte {
+	if len(b) > ViewLen {
+		return b[:ViewLen]
+	}
+	return b
+}
@@ -0,0 +1,44 @@
+package scan_test
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleSplitFunc() {
+	re := regexp.MustCompile(`\p{L}+|\d+|\s+|[,.]`)
+	in := bufio.NewScanner(strings.NewReader("Call me 42 times, please."))
+	in.Split(scan.SplitFunc(re))
+	for in.Scan() {
+		fmt.Printf("%q\n", in.Text())
+	}
+	fmt.Println(in.Err())
+
+	in = bufio.NewScanner(strings.NewReader("bad $"))
+	in.Split(scan.SplitFunc(re))
+	for in.Scan() {
+		fmt.Printf("%q\n", in.Text())
+	}
+	fmt.Println(in.Err())
+
+	// Output:
+	// "Call"
+	// " "
+	// "me"
+	// " "
+	// "42"
+	// " "
+	// "times"
+	// ","
+	// " "
+	// "please"
+	// "."
+	// <nil>
+	// "bad"
+	// " "
+	// failed to scan: "\\p{L}+|\\d+|\\s+|[,.]" does not match "$"
+}
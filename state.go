@@ -0,0 +1,131 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// State is the complete state of a scanner as written by Save and read
+// by Load. The buffer (B) is only included when requested.
+type State struct {
+	P       int      `json:"p"`
+	PP      int      `json:"pp"`
+	R       rune     `json:"r"`
+	Errors  []Error  `json:"errors,omitempty"`
+	NewLine []string `json:"newline,omitempty"`
+	Files   []File   `json:"files,omitempty"`
+	B       []byte   `json:"b,omitempty"`
+}
+
+// Save writes the State of the scanner as JSON so that a long batch
+// scan can be suspended and resumed later (see Load) or a failing state
+// shipped along with a bug report. The buffer (B) is only included if
+// withbuf is true since it is usually easier to buffer it again.
+func (s R) Save(w io.Writer, withbuf bool) error {
+	st := State{
+		P:       s.P,
+		PP:      s.PP,
+		R:       s.R,
+		Errors:  s.Errs(),
+		NewLine: s.NewLine,
+		Files:   s.Files,
+	}
+	if withbuf {
+		st.B = s.B
+	}
+	return json.NewEncoder(w).Encode(st)
+}
+
+// Load reads a State written by Save and restores it replacing the
+// buffer (B) only if it was saved. Returns an error (leaving the
+// scanner unchanged) if the State cannot be read or if its positions do
+// not fit within the buffer.
+func (s *R) Load(r io.Reader) error {
+	var st State
+	if err := json.NewDecoder(r).Decode(&st); err != nil {
+		return err
+	}
+	buf := s.B
+	if st.B != nil {
+		buf = st.B
+	}
+	if st.P < 0 || st.P > len(buf) || st.PP < 0 || st.PP > st.P {
+		return fmt.Errorf("saved state (%v,%v) does not fit buffer (%v)",
+			st.PP, st.P, len(buf))
+	}
+	if st.B != nil {
+		s.Gen++
+	}
+	s.B = buf
+	s.P, s.PP, s.R = st.P, st.PP, st.R
+	s.NewLine = st.NewLine
+	s.Files = st.Files
+	s.Errors = nil
+	for _, e := range st.Errors {
+		s.Errors = append(s.Errors, e)
+	}
+	s.ctr = counter{}
+	return nil
+}
+
+// MarshalJSON fulfills the json.Marshaler interface with a stable
+// snapshot of the scanner for debugging (not for restoring, see Save)
+// including the cursor, its Position, up to ViewLen bytes of context
+// before and after the last rune scanned (R), and the error stack. This
+// is what to ask for when users report grammar bugs.
+func (s R) MarshalJSON() ([]byte, error) {
+	before, _, after := s.excerpt()
+	snap := struct {
+		P      int      `json:"p"`
+		PP     int      `json:"pp"`
+		R      string   `json:"r"`
+		Pos    Position `json:"pos"`
+		Before string   `json:"before"`
+		After  string   `json:"after"`
+		Len    int      `json:"len"`
+		Errors []Error  `json:"errors,omitempty"`
+	}{
+		P:      s.P,
+		PP:     s.PP,
+		R:      string(s.R),
+		Pos:    s.Pos(),
+		Before: string(before),
+		After:  string(after),
+		Len:    len(s.B),
+		Errors: s.Errs(),
+	}
+	return json.Marshal(snap)
+}
+
+// Dump writes the complete state of the scanner in a human-readable
+// form (the cursor, its Position, an excerpt with the last rune scanned
+// in brackets, and every error in order) to standardize what gets
+// pasted into bug reports and debug logs. See MarshalJSON for the
+// machine-readable equivalent.
+func (s R) Dump(w io.Writer) {
+	before, last, after := s.excerpt()
+	fmt.Fprintf(w, "cursor:   P=%v PP=%v R=%q len=%v\n", s.P, s.PP, s.R, len(s.B))
+	fmt.Fprintf(w, "position: %v\n", s.Pos())
+	fmt.Fprintf(w, "excerpt:  %q[%q]%q\n", before, last, after)
+	errs := s.Errs()
+	fmt.Fprintf(w, "errors:   %v\n", len(errs))
+	for i, e := range errs {
+		fmt.Fprintf(w, "  %v. %v\n", i+1, e)
+	}
+}
+
+// excerpt returns up to ViewLen bytes before the last rune scanned, the
+// rune itself, and up to ViewLen bytes after it. The positions are
+// clamped to the buffer first since they may have been assigned
+// directly (and inconsistently).
+func (s R) excerpt() (before, last, after []byte) {
+	p := min(max(s.P, 0), len(s.B))
+	pp := min(max(s.PP, 0), p)
+	beg := max(pp-ViewLen, 0)
+	end := min(p+ViewLen, len(s.B))
+	return s.B[beg:pp], s.B[pp:p], s.B[p:end]
+}
@@ -0,0 +1,92 @@
+package scan_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Save() {
+	s := new(scan.R)
+	s.B = []byte("some thing")
+	s.Scan()
+	s.Scan()
+	s.Error("sample error")
+
+	var buf bytes.Buffer
+	fmt.Println(s.Save(&buf, false))
+	fmt.Print(buf.String())
+
+	s2 := new(scan.R)
+	s2.B = []byte("some thing")
+	fmt.Println(s2.Load(&buf))
+	s2.Print()
+	fmt.Println(s2.Errors)
+
+	s3 := new(scan.R)
+	buf.Reset()
+	s.Save(&buf, false)
+	fmt.Println(s3.Load(&buf))
+
+	// Output:
+	// <nil>
+	// {"p":2,"pp":1,"r":111,"errors":[{"pos":{"rune":111,"byte":2,"brune":2,"line":1,"lbyte":2,"lrune":2},"msg":"sample error"}]}
+	// <nil>
+	// 2 'o' "me thing"
+	// [sample error at U+006F 'o' 1,2-2 (2-2)]
+	// saved state (1,2) does not fit buffer (0)
+}
+
+func ExampleR_MarshalJSON() {
+	s := new(scan.R)
+	s.B = []byte("some thing that is a bit longer than ViewLen")
+	s.P = 25
+	s.Scan()
+	s.Error("sample error")
+
+	buf, err := json.Marshal(s)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(string(buf))
+
+	// Output:
+	// {"p":26,"pp":25,"r":"l","pos":{"rune":108,"byte":26,"brune":26,"line":1,"lbyte":26,"lrune":26},"before":"thing that is a bit ","after":"onger than ViewLen","len":44,"errors":[{"pos":{"rune":108,"byte":26,"brune":26,"line":1,"lbyte":26,"lrune":26},"msg":"sample error"}]}
+}
+
+func ExampleR_Dump() {
+	s := new(scan.R)
+	s.B = []byte("one line\nand another")
+	s.P = 11
+	s.Scan()
+	s.Error("sample error")
+	s.Errors = append(s.Errors, scan.Error{P: 3, Msg: "by offset"})
+
+	s.Dump(os.Stdout)
+
+	// Output:
+	// cursor:   P=12 PP=11 R='d' len=20
+	// position: U+0064 'd' 2,3-3 (12-12)
+	// excerpt:  "one line\nan"["d"]" another"
+	// errors:   2
+	//   1. sample error at U+0064 'd' 2,3-3 (12-12)
+	//   2. by offset at U+0065 'e' 1,3-3 (3-3)
+}
+
+func ExampleR_Dump_assigned() {
+	s := new(scan.R)
+	s.B = []byte("some thing")
+	s.ScanN(4)
+	s.P = 2 // assigned directly below PP
+
+	s.Dump(os.Stdout)
+
+	// Output:
+	// cursor:   P=2 PP=3 R='e' len=10
+	// position: U+006F 'o' 1,2-2 (2-2)
+	// excerpt:  "so"[""]"me thing"
+	// errors:   0
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// StreamChunk is the least number of bytes read from a stream at once.
+var StreamChunk = 4096
+
+// ErrWindow is returned when going back to a position of a stream that
+// is no longer retained (see Stream).
+var ErrWindow = errors.New("position behind retained stream window")
+
+// Stream sets the buffer to a sliding window over the reader (r) so
+// that inputs too large for memory (huge logs, pipes, network
+// protocols) can be scanned with a hard bound on memory. Scan (and
+// every other method looking ahead: End, Peek, PeekMatch, ScanByte, and
+// such) reads more as needed (in chunks) and drops everything more than
+// window bytes behind the position (P), adding the number of bytes
+// dropped to Base so that every Position (BufByte, lines, and columns)
+// still refers to the whole input.
+//
+// The window is therefore the most any grammar can backtrack as well
+// as the least that regular expressions (and therefore a Lexer) are
+// guaranteed to see ahead (so no Token can be longer). B never holds
+// more than the window, one chunk (the larger of StreamChunk and
+// window), and part of a rune. Going back further with a Pointer fails
+// loudly with ErrWindow (see Goto) but any other offsets saved from
+// before a Scan (Mark, Spans, Error.P, and such) are relative to B and
+// must be adjusted by the difference in Base by the caller. Read errors
+// (other than io.EOF) are added to Errors and end the stream.
+func (s *R) Stream(r io.Reader, window int) {
+	s.Buffer([]byte{})
+	if window < utf8.UTFMax {
+		window = utf8.UTFMax
+	}
+	s.src, s.window = r, window
+	s.hook()
+	s.origin.reset(s.newlines(), nil)
+}
+
+// ahead makes sure that at least n bytes are buffered after the
+// position (P) if streaming and the stream has not ended (see fill).
+func (s *R) ahead(n int) {
+	if s.src != nil && len(s.B)-s.P < n {
+		s.fill(n)
+	}
+}
+
+// fill reads from the stream until at least need bytes are left after
+// the position (P) or the stream ends (sliding the window first).
+func (s *R) fill(need int) {
+	for s.src != nil && len(s.B)-s.P < need {
+		s.slide()
+		chunk := max(StreamChunk, s.window)
+		if cap(s.B)-len(s.B) < chunk {
+			b := make([]byte, len(s.B), len(s.B)+chunk)
+			copy(b, s.B)
+			s.B = b
+		}
+		n, err := s.src.Read(s.B[len(s.B) : len(s.B)+chunk])
+		s.B = s.B[:len(s.B)+n]
+		if err != nil {
+			if err != io.EOF {
+				s.Errors = append(s.Errors, Error{P: s.P, Msg: err.Error()})
+			}
+			s.src = nil
+			s.hook()
+		}
+	}
+}
+
+// slide drops the bytes more than window behind the position (P).
+func (s *R) slide() {
+	d := s.P - s.window
+	if d <= 0 {
+		return
+	}
+	for d < s.P && !utf8.RuneStart(s.B[d]) {
+		d++
+	}
+	s.origin.to(s.B, d)
+	s.origin.at -= d
+	s.origin.nlend = max(0, s.origin.nlend-d)
+	s.origin.base += d
+	n := copy(s.B, s.B[d:])
+	s.B = s.B[:n]
+	s.P -= d
+	s.PP = max(0, s.PP-d)
+	s.Base += d
+	s.ctr = counter{}
+}
@@ -0,0 +1,86 @@
+package scan_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Stream() {
+	scan.StreamChunk = 16
+	defer func() { scan.StreamChunk = 4096 }()
+
+	log := strings.Repeat("ok\n", 1000) + "bad 👿 line\n" + strings.Repeat("ok\n", 10)
+	s := new(scan.R)
+	s.Stream(strings.NewReader(log), 8)
+
+	var ptr scan.Pointer
+	var most int
+	for s.Scan() {
+		most = max(most, len(s.B))
+		if s.R == 'b' {
+			ptr = s.Ptr()
+		}
+		if s.R == '👿' && len(s.Errors) == 0 {
+			s.Error("devil")
+			fmt.Println(s.Errors[0], s.Base)
+			fmt.Println(s.Goto(ptr), s.Pos())
+		}
+	}
+	fmt.Println(most, s.Pos(), s.Consumed())
+	fmt.Println(s.Goto(ptr))
+
+	// Output:
+	// devil at U+1F47F '👿' 1001,5-5 (3005-3008) 2981
+	// <nil> U+0062 'b' 1001,1-1 (3001-3001)
+	// 27 U+000A '\n' 1012,0-0 (3041-3044) 3044
+	// position behind retained stream window
+}
+
+func ExampleR_Stream_lexer() {
+	scan.StreamChunk = 16
+	defer func() { scan.StreamChunk = 4096 }()
+
+	in := strings.Repeat("x = 42\n", 500) + "last = 👿\n"
+	s := new(scan.R)
+	s.Stream(strings.NewReader(in), 32)
+
+	var n int
+	var last scan.Token
+	for t, err := range lexer.Tokens(s) {
+		if err != nil {
+			fmt.Println(err)
+			break
+		}
+		n++
+		last = t
+	}
+	fmt.Println(n, last.Text, last.Pos, len(s.B) < 64)
+
+	// Output:
+	// unexpected '👿' at U+1F47F '👿' 501,8-8 (3508-3511)
+	// 1502 = U+003D '=' 501,6-6 (3506-3506) true
+}
+
+func ExampleR_Find_stream() {
+	scan.StreamChunk = 16
+	defer func() { scan.StreamChunk = 4096 }()
+
+	log := strings.Repeat("ok\n", 1000) + "bad 👿 line\n" + strings.Repeat("ok\n", 10)
+	s := new(scan.R)
+	for _, re := range []*regexp.Regexp{
+		regexp.MustCompile(`bad`),
+		regexp.MustCompile(`b\w+ \S+`),
+		regexp.MustCompile(`missing`),
+	} {
+		s.Stream(strings.NewReader(log), 8)
+		fmt.Println(s.Find(re), s.Pos(), len(s.B))
+	}
+
+	// Output:
+	// true U+000A '\n' 1001,0-0 (3000-3000) 32
+	// true U+000A '\n' 1001,0-0 (3000-3000) 32
+	// false U+000A '\n' 1012,0-0 (3041-3044) 16
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"iter"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Token is the text matched by an expression and its Span within the
+// buffer. Type, Pos, and V are only set by a Lexer.
+type Token struct {
+	Span
+	Type int      // from the Rule that matched (see Lexer)
+	Text string   // matched text
+	Pos  Position // of first rune of the token
+	V    any      // typed value (see Rule.Value)
+}
+
+// goTo moves the position (P) to p and updates the previous position
+// (PP) and rune (R) as if the rune ending at p had just been scanned.
+func (s *R) goTo(p int) {
+	s.P = p
+	if p == 0 {
+		s.PP, s.R = 0, 0
+		return
+	}
+	r, ln := utf8.DecodeLastRune(s.B[:p])
+	s.PP, s.R = p-ln, r
+}
+
+// Tokens returns an iterator that lazily produces a Token for every
+// consecutive match of the regular expression (re) from the current
+// position (P) advancing the scanner past each as it goes. Iteration
+// ends at the end of the buffer or when nothing (or only an empty
+// string) matches in which case the Error is added to Errors and
+// produced with an empty Token as the final pair. Stopping early leaves
+// the scanner just after the last Token produced.
+func (s *R) Tokens(re *regexp.Regexp) iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		for !s.End() {
+			n := s.PeekMatch(re)
+			if n <= 0 {
+				s.Error(msg("expected %q", re))
+				yield(Token{}, s.Errors[len(s.Errors)-1])
+				return
+			}
+			sp := Span{s.P, s.P + n}
+			t := Token{Span: sp, Text: sp.Text(s.B)}
+			s.goTo(t.E)
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Fold calls the function (fn) with the accumulated value (starting
+// with acc) and the text of each consecutive match of the regular
+// expression (re) from the current position (P) advancing past each and
+// returning the final accumulated value. Stops (without error) when
+// nothing (or only an empty string) matches. This is useful when only
+// a result (a sum, a count) is wanted rather than the Tokens themselves.
+// If fn panics an Error is added and the value so far is returned.
+func (s *R) Fold(re *regexp.Regexp, acc any, fn func(acc any, matched string) any) any {
+	for !s.End() {
+		n := s.PeekMatch(re)
+		if n <= 0 {
+			break
+		}
+		text := string(s.B[s.P : s.P+n])
+		if !s.contain("Fold", func() { acc = fn(acc, text) }) {
+			break
+		}
+		s.goTo(s.P + n)
+	}
+	return acc
+}
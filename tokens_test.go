@@ -0,0 +1,50 @@
+package scan_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Tokens() {
+	s := new(scan.R)
+	s.B = []byte("one 22 three$")
+
+	for t, err := range s.Tokens(regexp.MustCompile(`\p{L}+|\d+|\s+`)) {
+		if err != nil {
+			fmt.Println(err)
+			break
+		}
+		fmt.Printf("%v-%v %q\n", t.B, t.E, t.Text)
+	}
+	s.Print()
+
+	// Output:
+	// 0-3 "one"
+	// 3-4 " "
+	// 4-6 "22"
+	// 6-7 " "
+	// 7-12 "three"
+	// expected "\\p{L}+|\\d+|\\s+" at U+0065 'e' 1,12-12 (12-12)
+	// 12 'e' "$"
+}
+
+func ExampleR_Fold() {
+	s := new(scan.R)
+	s.B = []byte("1 2 3 40 x")
+
+	sum := s.Fold(regexp.MustCompile(`\s*\d+`), 0,
+		func(acc any, matched string) any {
+			n, _ := strconv.Atoi(strings.TrimSpace(matched))
+			return acc.(int) + n
+		})
+	fmt.Println(sum)
+	s.Print()
+
+	// Output:
+	// 46
+	// 8 '0' " x"
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ANSI terminal escapes used when tracing with TraceColor
+const (
+	reset = "\x1b[0m"
+	dim   = "\x1b[2m"
+	red   = "\x1b[31m"
+	green = "\x1b[32m"
+	cyan  = "\x1b[36m"
+)
+
+// trace logs the scanner (see Log) in color when TraceColor is set.
+func (s R) trace() {
+	if (s.Trace|Trace)&TraceColor == 0 {
+		s.Log()
+		return
+	}
+	end := s.P + ViewLen
+	elided := "..."
+	if end > len(s.B) {
+		end = len(s.B)
+		elided = ""
+	}
+	s.output(fmt.Sprintf("%v%v%v %v%q%v %v%q%v%v",
+		dim, s.P, reset, green, s.R, reset, dim, s.B[s.P:end], elided, reset))
+}
+
+// trace logs the token scanner (see Log) in color along with the Stack
+// when TraceColor is set.
+func (t T) trace() {
+	if (t.Trace|Trace)&TraceColor == 0 {
+		t.Log()
+		return
+	}
+	output(t.Out, fmt.Sprintf("%v%v%v %v%v%v %v %v%q%v",
+		cyan, strings.Join(t.Stack, "/"), reset,
+		dim, t.P, reset, t.T.Type, green, t.T.Text, reset))
+}
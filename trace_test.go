@@ -0,0 +1,65 @@
+package scan_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleTraceColor() {
+	var out bytes.Buffer
+	s := new(scan.R)
+	s.Out = &out
+	s.Trace = 1 | scan.TraceColor
+	s.B = []byte("ab")
+	s.Scan()
+	s.Error("oops")
+
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fmt.Printf("%q\n", line)
+	}
+
+	// Output:
+	// "\x1b[2m1\x1b[0m \x1b[32m'a'\x1b[0m \x1b[2m\"b\"\x1b[0m"
+	// "\x1b[31merror: oops at U+0061 'a' 1,1-1 (1-1)\x1b[0m"
+}
+
+func ExampleT_Enter() {
+	t := &scan.T{Toks: []scan.Token{{Text: "1"}}}
+
+	term := func() {
+		defer t.Enter("Term")()
+		fmt.Println(t.Stack)
+	}
+	expr := func() {
+		defer t.Enter("Expr")()
+		term()
+		fmt.Println(t.Stack)
+	}
+	expr()
+	fmt.Println(t.Stack)
+
+	// Output:
+	// [Expr Term]
+	// [Expr]
+	// []
+}
+
+func ExampleTraceColor_tokens() {
+	var out bytes.Buffer
+	t := &scan.T{Toks: []scan.Token{{Type: 1, Text: "x"}}, Out: &out}
+	t.Trace = 1 | scan.TraceColor
+	defer t.Enter("Expr")()
+	t.Scan()
+	t.Error("oops")
+
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fmt.Printf("%q\n", line)
+	}
+
+	// Output:
+	// "\x1b[36mExpr\x1b[0m \x1b[2m1\x1b[0m 1 \x1b[32m\"x\"\x1b[0m"
+	// "\x1b[31merror: oops at U+0000 '\\x00' 0,0-0 (0-0)\x1b[0m"
+}
@@ -0,0 +1,182 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"fmt"
+	"io"
+)
+
+// T (as in scan.T or "token scanner") is the same as R except it scans
+// Tokens (usually from a Lexer) instead of runes so that parsers can
+// match Token types in a conventional two-phase (lex then parse)
+// design. Just like R, the position (P) can be changed directly but the
+// last Token (T) is only updated by Scan.
+type T struct {
+	Toks   []Token   // full buffer of tokens for lookahead or behind
+	P      int       // index in Toks, points *after* T
+	PP     int       // index of previous Scan, points *to* T
+	T      Token     // last scanned
+	Trace  int       // activate trace log (>0)
+	Errors []error   // stack of errors in order
+	Stack  []string  // names of rules entered (see Enter)
+	Out    io.Writer // for Log and traces (log if nil, see R.Out)
+}
+
+// Enter pushes the name of a rule onto the Stack (shown when tracing)
+// and returns the function to pop it so that hand-written rules can
+// begin with a single line: defer t.Enter("Expr")()
+func (t *T) Enter(name string) func() {
+	t.Stack = append(t.Stack, name)
+	return func() { t.Stack = t.Stack[:len(t.Stack)-1] }
+}
+
+// String implements fmt.Stringer with the position (P) and the type and
+// quoted text of the last Token (T).
+func (t T) String() string {
+	return fmt.Sprintf("%v %v %q", t.P, t.T.Type, t.T.Text)
+}
+
+// Print is shorthand for fmt.Println(t).
+func (t T) Print() { fmt.Println(t) }
+
+// Log is shorthand for log.Print(t) unless Out is set in which case
+// it is written there instead.
+func (t T) Log() { output(t.Out, t.String()) }
+
+// Scan sets the next Token to T and advances the position (P) returning
+// false when there is nothing left to scan.
+func (t *T) Scan() bool {
+	if t.P >= len(t.Toks) {
+		return false
+	}
+	t.PP = t.P
+	t.T = t.Toks[t.P]
+	t.P++
+	if t.Trace > 0 || Trace > 0 {
+		t.trace()
+	}
+	return true
+}
+
+// End returns true if there are no more Tokens to scan.
+func (t *T) End() bool { return t.P >= len(t.Toks) }
+
+// Reset returns the token scanner to the first Token (which are kept)
+// clearing the last Token (T), Errors, and Stack so that the same Tokens
+// can be parsed again.
+func (t *T) Reset() {
+	t.P, t.PP, t.T = 0, 0, Token{}
+	t.Errors = t.Errors[:0]
+	t.Stack = t.Stack[:0]
+}
+
+// Mark returns the main state values in order to jump Back when
+// required during other scan operations.
+func (t *T) Mark() (Token, int, int) { return t.T, t.P, t.PP }
+
+// Back restores the main state of the scanner (see Mark).
+func (t *T) Back(tok Token, p int, pp int) { t.T, t.P, t.PP = tok, p, pp }
+
+// Is returns true if the types match those of the last scanned Token
+// (T) and the Tokens following it in order.
+func (t *T) Is(types ...int) bool { return t.P > 0 && t.match(t.PP, types) }
+
+// Peek returns true if the types match those of the Tokens from the
+// current position (P) forward without advancing.
+func (t *T) Peek(types ...int) bool { return t.match(t.P, types) }
+
+func (t *T) match(p int, types []int) bool {
+	if p+len(types) > len(t.Toks) {
+		return false
+	}
+	for i, typ := range types {
+		if t.Toks[p+i].Type != typ {
+			return false
+		}
+	}
+	return true
+}
+
+// In returns true if the type of the next Token (from the current
+// position, see Peek) is any of the types.
+func (t *T) In(types ...int) bool {
+	if t.P >= len(t.Toks) {
+		return false
+	}
+	for _, typ := range types {
+		if t.Toks[t.P].Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Error adds an Error with the message (fmt.Sprintf arguments or
+// DefaultErrorMessage) and the Position of the next Token (or the last
+// if at the end) since that is usually the one that was not expected.
+func (t *T) Error(a ...any) {
+	m := msg(DefaultErrorMessage)
+	if len(a) > 0 {
+		form, _ := a[0].(string)
+		m = fmt.Sprintf(form, a[1:]...)
+	}
+	tok := t.T
+	if t.P < len(t.Toks) {
+		tok = t.Toks[t.P]
+	}
+	t.Errors = append(t.Errors, Error{P: tok.B + 1, Pos: tok.Pos, Msg: m})
+	if (t.Trace|Trace)&TraceColor != 0 {
+		output(t.Out, red+"error: "+t.Errors[len(t.Errors)-1].Error()+reset)
+	}
+}
+
+// BadStmt is the node produced by Statements in place of a statement
+// that failed to parse (see go/ast.BadStmt). Span covers the Tokens that
+// were skipped and Err is the first Error added by the failed attempt.
+type BadStmt struct {
+	Span
+	Err error
+}
+
+// Statements calls stmt (which must consume its own terminator when it
+// succeeds) until the end of the Tokens returning the node of each and
+// whether all succeeded. This packages the most common panic-mode error
+// recovery for line and statement oriented input: whenever stmt fails
+// the position is moved back to the beginning of the statement, every
+// Token through the next of the terminator types (term) is skipped, and
+// a BadStmt is added in place of the statement before continuing with
+// the next. If stmt did not add an Error one is added for it. A stmt
+// that succeeds without consuming any Tokens is treated as a failure
+// so that recovery always makes progress.
+func (t *T) Statements(stmt func(t *T) (any, bool), term ...int) ([]any, bool) {
+	var nodes []any
+	all := true
+	for !t.End() {
+		tok, pos, prev := t.Mark()
+		errs := len(t.Errors)
+		v, ok := stmt(t)
+		if ok && t.P > pos {
+			nodes = append(nodes, v)
+			continue
+		}
+		all = false
+		t.Back(tok, pos, prev)
+		if len(t.Errors) == errs {
+			t.Error(msg("invalid statement"))
+		}
+		bad := BadStmt{Span{t.Toks[t.P].B, 0}, t.Errors[errs]}
+	SKIP:
+		for t.Scan() {
+			for _, typ := range term {
+				if t.T.Type == typ {
+					break SKIP
+				}
+			}
+		}
+		bad.E = t.T.E
+		nodes = append(nodes, bad)
+	}
+	return nodes, all
+}
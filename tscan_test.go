@@ -0,0 +1,125 @@
+package scan_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleT() {
+	s := new(scan.R)
+	s.B = []byte("x = 42 y")
+	toks, _ := lexer.Lex(s)
+
+	t := scan.T{Toks: toks}
+	fmt.Println(t.Is(Ident), t.Peek(Ident, Op, Number))
+	t.Scan()
+	fmt.Println(t.Is(Ident, Op, Number), t.Peek(Op, Number))
+	t.Print()
+
+	t.Scan()
+	t.Scan()
+	fmt.Println(t.In(Op, Number))
+	if !t.In(Op) {
+		t.Error("expected operator")
+	}
+	fmt.Println(t.Errors)
+	fmt.Println(t.Scan(), t.Scan(), t.End())
+
+	// Output:
+	// false true
+	// true true
+	// 1 1 "x"
+	// false
+	// [expected operator at U+0079 'y' 1,8-8 (8-8)]
+	// true false true
+}
+
+func ExampleT_Statements() {
+	lexer, _ := scan.MustParseLexer(`
+-space [ \t]+
+End    \n|;
+Num    \d+
+Name   \p{L}+
+Set    =
+`)
+	const (
+		End = iota + 1
+		Num
+		Name
+		Set
+	)
+
+	// stmt <- Name '=' (Num / Name) End
+	stmt := func(t *scan.T) (any, bool) {
+		if !t.Peek(Name, Set) {
+			t.Error("expected assignment")
+			return nil, false
+		}
+		t.Scan()
+		name := t.T.Text
+		t.Scan()
+		if !t.In(Num, Name) {
+			t.Error("expected value")
+			return nil, false
+		}
+		t.Scan()
+		val := t.T.Text
+		if !t.In(End) {
+			return nil, false
+		}
+		t.Scan()
+		return name + ":" + val, true
+	}
+
+	s := new(scan.R)
+	s.Buffer("a = 1\nb = = 2; c = b\n4 d\ne = 5\n")
+	toks, _ := lexer.Lex(s)
+	t := &scan.T{Toks: toks}
+	nodes, ok := t.Statements(stmt, End)
+	for _, n := range nodes {
+		fmt.Printf("%v\n", n)
+	}
+	fmt.Println(ok)
+
+	// Output:
+	// a:1
+	// {{6 14} expected value at U+003D '=' 2,5-5 (11-11)}
+	// c:b
+	// {{21 25} expected assignment at U+0034 '4' 3,1-1 (22-22)}
+	// e:5
+	// false
+}
+
+func ExampleT_Statements_empty() {
+	t := new(scan.T)
+	t.Toks = []scan.Token{
+		{Span: scan.Span{B: 0, E: 1}, Type: 1, Text: "x"},
+		{Span: scan.Span{B: 1, E: 2}, Type: 2, Text: ";"},
+	}
+
+	// succeeds without consuming anything
+	empty := func(t *scan.T) (any, bool) { return "empty", true }
+
+	nodes, ok := t.Statements(empty, 2)
+	fmt.Println(nodes, ok)
+
+	// Output:
+	// [{{0 2} invalid statement at U+0000 '\x00' 0,0-0 (0-0)}] false
+}
+
+func ExampleT_Reset() {
+	t := new(scan.T)
+	t.Toks = []scan.Token{{Type: 1, Text: "x"}, {Type: 2, Text: "+"}}
+	t.Enter("Expr")
+	t.Scan()
+	t.Error("sample error")
+	fmt.Println(t, t.Stack, len(t.Errors))
+
+	t.Reset()
+	fmt.Println(t, t.Stack, len(t.Errors))
+
+	// Output:
+	// 1 1 "x" [Expr] 1
+	// 0 0 "" [] 0
+}
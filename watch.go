@@ -0,0 +1,41 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import "regexp"
+
+type watch struct {
+	re *regexp.Regexp // anchored
+	fn func(match Span)
+}
+
+// Watch registers the regular expression (re) such that whenever Scan
+// passes a position (the beginning of the rune scanned) where it
+// matches (anything but an empty string) the function is called with
+// the Span of the match. The position and main parse are not affected
+// making this useful for collecting secondary information (TODO
+// markers, line directives, and such) in the same pass. Only positions
+// actually scanned by Scan are checked (not those skipped by matching
+// methods or the Lexer). A nil expression removes all watches.
+func (s *R) Watch(re *regexp.Regexp, fn func(match Span)) {
+	defer s.hook()
+	if re == nil {
+		s.watches = nil
+		return
+	}
+	anchored := regexp.MustCompile(`^(?:` + re.String() + `)`)
+	s.watches = append(s.watches, watch{anchored, fn})
+}
+
+// watch calls the function of every watch matching at PP (see Scan).
+func (s *R) watch() {
+	for _, w := range s.watches {
+		loc := w.re.FindIndex(s.B[s.PP:])
+		if loc == nil || loc[1] == 0 {
+			continue
+		}
+		sp := Span{s.PP, s.PP + loc[1]}
+		s.contain("Watch", func() { w.fn(sp) })
+	}
+}
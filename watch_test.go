@@ -0,0 +1,34 @@
+package scan_test
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/rwxrob/scan"
+)
+
+func ExampleR_Watch() {
+	s := new(scan.R)
+	s.Buffer("a := 1 // TODO: name\nb := 2 // TODO(rob): two\n")
+
+	var todos []string
+	s.Watch(regexp.MustCompile(`TODO(\(\w+\))?:`), func(m scan.Span) {
+		todos = append(todos, fmt.Sprintf("%v %v", m, s.Positions(m.B + 1)[0]))
+	})
+
+	var count int
+	for s.Scan() {
+		if s.R == ':' {
+			count++
+		}
+	}
+	fmt.Println(count)
+	for _, t := range todos {
+		fmt.Println(t)
+	}
+
+	// Output:
+	// 4
+	// {10 15} U+0054 'T' 1,11-11 (11-11)
+	// {31 41} U+0054 'T' 2,11-11 (32-32)
+}